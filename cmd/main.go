@@ -1,182 +1,683 @@
-package main
-
-import (
-	"context"
-	"errors"
-	"net/http"
-	"os/signal"
-	"sync/atomic"
-	"syscall"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"go.uber.org/zap"
-
-	"github.com/duynhne/shipping-service/config"
-	database "github.com/duynhne/shipping-service/internal/core"
-	"github.com/duynhne/shipping-service/internal/core/repository/postgres"
-	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
-	webv1 "github.com/duynhne/shipping-service/internal/web/v1"
-	"github.com/duynhne/shipping-service/middleware"
-)
-
-func main() {
-	cfg := config.Load()
-	if err := cfg.Validate(); err != nil {
-		panic("Configuration validation failed: " + err.Error())
-	}
-
-	logger, err := middleware.NewLogger()
-	if err != nil {
-		panic("Failed to initialize logger: " + err.Error())
-	}
-	defer func() { _ = logger.Sync() }()
-
-	logger.Info("Service starting",
-		zap.String("service", cfg.Service.Name),
-		zap.String("version", cfg.Service.Version),
-		zap.String("env", cfg.Service.Env),
-		zap.String("port", cfg.Service.Port),
-	)
-
-	pool, err := database.Connect(context.Background())
-	if err != nil {
-		logger.Error("Failed to connect to database", zap.Error(err))
-		return
-	}
-	defer pool.Close()
-	logger.Info("Database connection pool established")
-
-	tp := initTracing(cfg, logger)
-
-	initProfiling(cfg, logger)
-
-	// Initialize dependencies
-	shippingRepo := postgres.NewShipmentRepository(pool)
-	shippingService := logicv1.NewShippingService(shippingRepo)
-	shippingHandler := webv1.NewHandler(shippingService)
-
-	var isShuttingDown atomic.Bool
-	srv := setupServer(cfg, logger, &isShuttingDown, shippingHandler)
-	runGracefulShutdown(cfg, srv, tp, pool, logger, &isShuttingDown)
-}
-
-func initTracing(cfg *config.Config, logger *zap.Logger) interface{ Shutdown(context.Context) error } {
-	if !cfg.Tracing.Enabled {
-		logger.Info("Tracing disabled (TRACING_ENABLED=false)")
-		return nil
-	}
-	tp, err := middleware.InitTracing(cfg)
-	if err != nil {
-		logger.Warn("Failed to initialize tracing", zap.Error(err))
-		return nil
-	}
-	logger.Info("Tracing initialized",
-		zap.String("endpoint", cfg.Tracing.Endpoint),
-		zap.Float64("sample_rate", cfg.Tracing.SampleRate),
-	)
-	return tp
-}
-
-func initProfiling(cfg *config.Config, logger *zap.Logger) {
-	if !cfg.Profiling.Enabled {
-		logger.Info("Profiling disabled (PROFILING_ENABLED=false)")
-		return
-	}
-	if err := middleware.InitProfiling(); err != nil {
-		logger.Warn("Failed to initialize profiling", zap.Error(err))
-		return
-	}
-	logger.Info("Profiling initialized", zap.String("endpoint", cfg.Profiling.Endpoint))
-}
-
-func setupServer(cfg *config.Config, logger *zap.Logger, isShuttingDown *atomic.Bool, handler *webv1.Handler) *http.Server {
-	r := gin.Default()
-
-	r.Use(middleware.TracingMiddleware())
-	r.Use(middleware.LoggingMiddleware(logger))
-	r.Use(middleware.PrometheusMiddleware())
-
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
-	r.GET("/ready", func(c *gin.Context) {
-		if isShuttingDown.Load() {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
-	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
-
-	// Shipping v1 routes — Variant A edge naming (see api-naming-convention.md)
-
-	// Public: customer-facing tracking + estimation (no auth required)
-	r.GET("/shipping/v1/public/track", handler.TrackShipment)
-	r.GET("/shipping/v1/public/estimate", handler.EstimateShipping)
-
-	// Internal: called by order-service for order-detail aggregation. Not on gateway.
-	r.GET("/shipping/v1/internal/orders/:orderId", handler.GetShipmentByOrder)
-
-	return &http.Server{
-		Addr:              ":" + cfg.Service.Port,
-		Handler:           r,
-		ReadHeaderTimeout: 10 * time.Second,
-	}
-}
-
-func runGracefulShutdown(
-	cfg *config.Config,
-	srv *http.Server,
-	tp interface{ Shutdown(context.Context) error },
-	pool interface{ Close() },
-	logger *zap.Logger,
-	isShuttingDown *atomic.Bool,
-) {
-	go func() {
-		logger.Info("Starting shipping service", zap.String("port", cfg.Service.Port))
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Error("Failed to start server", zap.Error(err))
-		}
-	}()
-
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
-	defer stop()
-
-	<-ctx.Done()
-	logger.Info("Shutdown signal received")
-
-	isShuttingDown.Store(true)
-	drainDelay := cfg.GetReadinessDrainDelayDuration()
-	if drainDelay > 0 {
-		logger.Info("Readiness drain delay started", zap.Duration("delay", drainDelay))
-		time.Sleep(drainDelay)
-	}
-
-	shutdownTimeout := cfg.GetShutdownTimeoutDuration()
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
-	defer cancel()
-
-	logger.Info("Shutting down server...", zap.Duration("timeout", shutdownTimeout))
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		logger.Error("HTTP server shutdown error", zap.Error(err))
-	} else {
-		logger.Info("HTTP server shutdown complete")
-	}
-
-	pool.Close()
-	logger.Info("Database pool closed")
-
-	if tp != nil {
-		if err := tp.Shutdown(shutdownCtx); err != nil {
-			logger.Error("Tracer shutdown error", zap.Error(err))
-		} else {
-			logger.Info("Tracer shutdown complete")
-		}
-	}
-
-	middleware.StopProfiling()
-	logger.Info("Graceful shutdown complete")
-}
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/duynhne/shipping-service/config"
+	"github.com/duynhne/shipping-service/internal/auth"
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/core/repository/cache"
+	"github.com/duynhne/shipping-service/internal/core/repository/postgres"
+	grpcv1 "github.com/duynhne/shipping-service/internal/grpc/v1"
+	"github.com/duynhne/shipping-service/internal/health"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/internal/notify"
+	"github.com/duynhne/shipping-service/internal/outbox"
+	"github.com/duynhne/shipping-service/internal/stream"
+	webv1 "github.com/duynhne/shipping-service/internal/web/v1"
+	"github.com/duynhne/shipping-service/internal/webhook"
+	"github.com/duynhne/shipping-service/middleware"
+)
+
+func main() {
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		panic("Configuration validation failed: " + err.Error())
+	}
+
+	logger, logLevel, err := middleware.NewLogger(cfg)
+	if err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+	defer func() { _ = logger.Sync() }()
+	logger, lp := initOTelLogs(cfg, logger, logLevel)
+	zap.ReplaceGlobals(logger)
+
+	logger.Info("Service starting",
+		zap.String("service", cfg.Service.Name),
+		zap.String("version", cfg.Service.Version),
+		zap.String("env", cfg.Service.Env),
+		zap.String("port", cfg.Service.Port),
+	)
+
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return
+	}
+	defer db.Close()
+	logger.Info("Database connection pool established")
+	prometheus.MustRegister(database.NewPoolCollector(db))
+	pool := db.Primary()
+
+	telemetry := otelProviders{tracer: initTracing(cfg, logger), meter: initOTelMetrics(cfg, logger), logs: lp}
+
+	initProfiling(cfg, logger)
+
+	// Initialize dependencies
+	shippingRepo, shipmentCache := newShipmentRepository(cfg, db, logger)
+	shippingService := logicv1.NewShippingService(shippingRepo)
+	ownership := initTrackingVerification(cfg, logger)
+	shippingHandler := webv1.NewHandler(shippingService, ownership)
+	var exceptionRepo domain.ShipmentExceptionRepository = postgres.NewExceptionRepository(db)
+	if shipmentCache != nil {
+		exceptionRepo = cache.NewExceptionRepository(exceptionRepo, shipmentCache)
+	}
+	exceptionService := logicv1.NewExceptionService(exceptionRepo)
+	exceptionHandler := webv1.NewExceptionHandler(exceptionService)
+	subscriptionRepo := postgres.NewSubscriptionRepository(pool)
+	subscriptionService := logicv1.NewSubscriptionService(subscriptionRepo)
+	subscriptionHandler := webv1.NewSubscriptionHandler(subscriptionService, shippingService, ownership)
+
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhookService := logicv1.NewWebhookService(webhookRepo)
+	webhookHandler := webv1.NewWebhookHandler(webhookService)
+
+	hub := stream.NewHub()
+	streamHandler := webv1.NewStreamHandler(shippingService, ownership, hub, cfg.Stream.HeartbeatInterval)
+
+	publisher, err := newOutboxPublisher(cfg, subscriptionRepo, webhookRepo)
+	if err != nil {
+		logger.Error("Failed to initialize outbox publisher", zap.Error(err))
+		return
+	}
+	readiness := newReadiness(cfg, db)
+	workers := []backgroundWorker{{name: "Read replica health checks", stop: db.Stop}}
+	if relay := initOutboxRelay(cfg, postgres.NewOutboxRepository(pool), publisher, logger); relay != nil {
+		workers = append(workers, backgroundWorker{name: "Outbox relay", stop: relay.Stop})
+		readiness.Register(health.Check{Name: "outbox_relay_lag", Func: relay.LagCheck(cfg.Readiness.OutboxMaxLag)})
+	}
+	if worker := initWebhookWorker(cfg, webhookRepo, logger); worker != nil {
+		workers = append(workers, backgroundWorker{name: "Webhook worker", stop: worker.Stop})
+	}
+	if worker := initNotificationWorker(cfg, subscriptionRepo, logger); worker != nil {
+		workers = append(workers, backgroundWorker{name: "Notification worker", stop: worker.Stop})
+	}
+	feederCfg := stream.FeederConfig{
+		PollInterval: cfg.Stream.PollInterval,
+		BatchSize:    cfg.Stream.BatchSize,
+	}
+	if shipmentCache != nil {
+		// Drop shipments changed by any replica, including exceptions opened or resolved
+		feederCfg.OnEvent = func(event domain.TrackingEvent) {
+			shipmentCache.Invalidate(context.Background(), event.TrackingNumber)
+		}
+	}
+	feeder := stream.NewFeeder(postgres.NewTrackingEventRepository(pool), hub, feederCfg, logger)
+	feeder.Start()
+	workers = append(workers, backgroundWorker{name: "Tracking stream feeder", stop: feeder.Stop})
+
+	authenticator, err := initAuth(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to initialize internal API authentication", zap.Error(err))
+		return
+	}
+
+	// One limiter serves the public HTTP routes and gRPC methods
+	limiter := newRateLimiter(cfg, authenticator, logger)
+
+	var isShuttingDown atomic.Bool
+	srv := setupServer(cfg, logger, &isShuttingDown, readiness, limiter, webv1.Handlers{
+		Shipping:     shippingHandler,
+		Exception:    exceptionHandler,
+		Subscription: subscriptionHandler,
+		Webhook:      webhookHandler,
+		Stream:       streamHandler,
+		LogLevel:     webv1.NewLogLevelHandler(logLevel),
+		Auth:         authenticator,
+		Idempotency:  initIdempotency(cfg, postgres.NewIdempotencyRepository(pool), logger),
+	})
+	// Open SSE streams never go idle, so end them as soon as Shutdown starts
+	srv.RegisterOnShutdown(hub.Close)
+	grpcSrv := setupGRPCServer(cfg, grpcv1.NewServer(shippingService, ownership, logger), authenticator, limiter, logger)
+	runGracefulShutdown(cfg, srv, grpcSrv, telemetry, db, workers, logger, &isShuttingDown)
+}
+
+// newShipmentRepository returns the shipment repository, behind the tracking
+// lookup cache when enabled; the cache is nil otherwise.
+func newShipmentRepository(
+	cfg *config.Config, db *database.Cluster, logger *zap.Logger,
+) (domain.ShipmentRepository, *cache.ShipmentRepository) {
+	repo := postgres.NewShipmentRepository(db)
+	if !cfg.Cache.Enabled {
+		logger.Info("Shipment cache disabled (SHIPMENT_CACHE_ENABLED=false)")
+		return repo, nil
+	}
+
+	cached := cache.NewShipmentRepository(repo, nil, cache.Config{
+		Size:        cfg.Cache.Size,
+		TTL:         cfg.Cache.TTL,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+	}, logger)
+	logger.Info("Shipment cache enabled", zap.Int("size", cfg.Cache.Size), zap.Duration("ttl", cfg.Cache.TTL))
+	return cached, cached
+}
+
+// newReadiness creates the /ready check registry with the database checks.
+// Other components register their own checks.
+func newReadiness(cfg *config.Config, db *database.Cluster) *health.Registry {
+	readiness := health.NewRegistry(cfg.Readiness.CheckTimeout, cfg.Readiness.CacheTTL)
+	readiness.Register(health.Check{Name: "postgres", Func: db.Ping, Critical: true})
+	if db.HasReplicas() {
+		// Reads fall back to the primary, so lost replicas only degrade the service
+		readiness.Register(health.Check{Name: "postgres_read_replicas", Func: db.ReplicaStatus})
+	}
+	return readiness
+}
+
+// backgroundWorker is a polling loop stopped during graceful shutdown, before the pool closes.
+type backgroundWorker struct {
+	name string
+	stop func()
+}
+
+// newOutboxPublisher builds the publishers fed by the outbox relay: merchant webhooks,
+// the configured event sink for other services and, when enabled, customer notifications.
+// Each subscriber has its own delivery queue, so only the sink can fail an event.
+func newOutboxPublisher(
+	cfg *config.Config, subscriptions *postgres.SubscriptionRepository, webhooks *postgres.WebhookRepository,
+) (outbox.Publisher, error) {
+	// Merchant webhooks are only queued here; webhook.Worker delivers them.
+	publishers := outbox.MultiPublisher{webhook.NewDispatcher(webhooks)}
+
+	switch cfg.Outbox.Publisher {
+	case "webhook":
+		publishers = append(publishers, outbox.NewWebhookPublisher(cfg.Outbox.WebhookURL, cfg.Outbox.PublishTimeout))
+	case "file":
+		f, err := os.OpenFile(cfg.Outbox.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("open outbox file %q: %w", cfg.Outbox.FilePath, err)
+		}
+		publishers = append(publishers, outbox.NewWriterPublisher(f))
+	default:
+		publishers = append(publishers, outbox.NewWriterPublisher(os.Stdout))
+	}
+
+	if cfg.Notification.Enabled {
+		// Notifications are only queued here; notify.Worker sends them.
+		publishers = append(publishers, notify.NewDispatcher(subscriptions))
+	}
+
+	return publishers, nil
+}
+
+// initOutboxRelay starts the shipment outbox relay, or returns nil when disabled.
+func initOutboxRelay(
+	cfg *config.Config, repo *postgres.OutboxRepository, publisher outbox.Publisher, logger *zap.Logger,
+) *outbox.Relay {
+	if !cfg.Outbox.Enabled {
+		logger.Info("Outbox relay disabled (OUTBOX_RELAY_ENABLED=false)")
+		return nil
+	}
+
+	// The lease covers a whole batch of worst-case publishes so another replica
+	// cannot re-claim events this relay is still working through.
+	relay := outbox.NewRelay(repo, publisher, outbox.RelayConfig{
+		PollInterval:   cfg.Outbox.PollInterval,
+		BatchSize:      cfg.Outbox.BatchSize,
+		Lease:          cfg.Outbox.PublishTimeout * time.Duration(cfg.Outbox.BatchSize+1),
+		PublishTimeout: cfg.Outbox.PublishTimeout,
+		MaxAttempts:    cfg.Outbox.MaxAttempts,
+		Backoff:        outbox.Backoff{Initial: cfg.Outbox.InitialBackoff, Max: cfg.Outbox.MaxBackoff},
+	}, logger)
+	relay.Start()
+
+	logger.Info("Outbox relay started",
+		zap.String("publisher", cfg.Outbox.Publisher),
+		zap.Duration("poll_interval", cfg.Outbox.PollInterval),
+		zap.Bool("notifications", cfg.Notification.Enabled),
+	)
+	return relay
+}
+
+// initIdempotency builds the Idempotency-Key handler, or returns nil when disabled.
+func initIdempotency(cfg *config.Config, repo *postgres.IdempotencyRepository, logger *zap.Logger) *webv1.Idempotency {
+	if !cfg.Idempotency.Enabled {
+		logger.Info("Idempotency-Key handling disabled (IDEMPOTENCY_ENABLED=false)")
+		return nil
+	}
+	return webv1.NewIdempotency(repo, webv1.IdempotencyConfig{
+		TTL:          cfg.Idempotency.TTL,
+		LockTimeout:  cfg.Idempotency.LockTimeout,
+		MaxBodyBytes: int64(cfg.Idempotency.MaxBodyBytes),
+	})
+}
+
+// initAuth builds the internal API authenticator, or returns nil when disabled.
+func initAuth(cfg *config.Config, logger *zap.Logger) (*auth.Authenticator, error) {
+	if !cfg.Auth.Enabled {
+		logger.Warn("Internal API authentication disabled (AUTH_ENABLED=false)")
+		return nil, nil
+	}
+
+	authCfg := auth.Config{Issuer: cfg.Auth.Issuer, Audience: cfg.Auth.Audience}
+	switch {
+	case cfg.Auth.JWKSFile != "":
+		keys, err := auth.LoadJWKSFile(cfg.Auth.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		authCfg.Keys = keys
+	case cfg.Auth.JWKSURL != "":
+		keys := auth.NewRemoteKeySet(cfg.Auth.JWKSURL, cfg.Auth.JWKSRefreshInterval)
+		// An unreachable identity provider must not block startup; keys are fetched again on demand.
+		if err := keys.Refresh(context.Background()); err != nil {
+			logger.Warn("Failed to fetch JWKS, retrying on first request", zap.Error(err))
+		}
+		authCfg.Keys = keys
+	}
+	if cfg.Auth.APIKeysFile != "" {
+		apiKeys, err := auth.LoadAPIKeys(cfg.Auth.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		authCfg.APIKeys = apiKeys
+	}
+
+	logger.Info("Internal API authentication enabled",
+		zap.Bool("jwt", authCfg.Keys != nil),
+		zap.Int("api_keys", len(authCfg.APIKeys)),
+	)
+	return auth.New(authCfg), nil
+}
+
+// initTrackingVerification returns the public tracking ownership verifier, or nil when disabled.
+func initTrackingVerification(cfg *config.Config, logger *zap.Logger) *logicv1.OwnershipVerifier {
+	if !cfg.Tracking.VerificationEnabled {
+		logger.Info("Tracking verification disabled (TRACKING_VERIFICATION_ENABLED=false)")
+		return nil
+	}
+	logger.Info("Tracking verification enabled", zap.Duration("token_ttl", cfg.Tracking.TokenTTL))
+	return logicv1.NewOwnershipVerifier(cfg.Tracking.TokenSecret, cfg.Tracking.TokenTTL)
+}
+
+func initTracing(cfg *config.Config, logger *zap.Logger) interface{ Shutdown(context.Context) error } {
+	if !cfg.Tracing.Enabled {
+		logger.Info("Tracing disabled (TRACING_ENABLED=false)")
+		return nil
+	}
+	tp, err := middleware.InitTracing(cfg)
+	if err != nil {
+		logger.Warn("Failed to initialize tracing", zap.Error(err))
+		return nil
+	}
+	logger.Info("Tracing initialized",
+		zap.String("exporter", cfg.Tracing.Exporter),
+		zap.String("protocol", cfg.Tracing.Protocol),
+		zap.String("endpoint", cfg.Tracing.Endpoint),
+		zap.String("sampler", cfg.Tracing.Sampler),
+		zap.Float64("sample_rate", cfg.Tracing.SampleRate),
+	)
+	return tp
+}
+
+// otelProviders are the OpenTelemetry providers flushed on shutdown; nil when disabled.
+type otelProviders struct {
+	tracer interface{ Shutdown(context.Context) error }
+	meter  interface{ Shutdown(context.Context) error }
+	logs   interface{ Shutdown(context.Context) error }
+}
+
+func initOTelMetrics(cfg *config.Config, logger *zap.Logger) interface{ Shutdown(context.Context) error } {
+	if !cfg.OTelMetrics.Enabled {
+		logger.Info("OpenTelemetry metrics disabled (OTEL_METRICS_ENABLED=false)")
+		return nil
+	}
+	mp, err := middleware.InitMetrics(cfg)
+	if err != nil {
+		logger.Warn("Failed to initialize OpenTelemetry metrics", zap.Error(err))
+		return nil
+	}
+	logger.Info("OpenTelemetry metrics initialized",
+		zap.String("exporter", cfg.OTelMetrics.Exporter),
+		zap.Duration("interval", cfg.OTelMetrics.Interval),
+	)
+	return mp
+}
+
+// initOTelLogs returns logger extended to also export to OpenTelemetry logs,
+// with the provider to shut down, or logger unchanged when disabled.
+func initOTelLogs(
+	cfg *config.Config,
+	logger *zap.Logger,
+	level zap.AtomicLevel,
+) (*zap.Logger, interface{ Shutdown(context.Context) error }) {
+	if !cfg.OTelLogs.Enabled {
+		return logger, nil
+	}
+	lp, err := middleware.InitLogs(cfg)
+	if err != nil {
+		logger.Warn("Failed to initialize OpenTelemetry logs", zap.Error(err))
+		return logger, nil
+	}
+	bridged, err := middleware.WithOTelLogs(logger, lp, level)
+	if err != nil {
+		logger.Warn("Failed to bridge logs to OpenTelemetry", zap.Error(err))
+		_ = lp.Shutdown(context.Background())
+		return logger, nil
+	}
+	bridged.Info("OpenTelemetry logs initialized", zap.String("endpoint", cfg.Tracing.Endpoint))
+	return bridged, lp
+}
+
+func initProfiling(cfg *config.Config, logger *zap.Logger) {
+	if !cfg.Profiling.Enabled {
+		logger.Info("Profiling disabled (PROFILING_ENABLED=false)")
+		return
+	}
+	if err := middleware.InitProfiling(); err != nil {
+		logger.Warn("Failed to initialize profiling", zap.Error(err))
+		return
+	}
+	logger.Info("Profiling initialized", zap.String("endpoint", cfg.Profiling.Endpoint))
+}
+
+// initWebhookWorker starts the merchant webhook delivery worker, or returns nil when disabled.
+func initWebhookWorker(cfg *config.Config, repo *postgres.WebhookRepository, logger *zap.Logger) *webhook.Worker {
+	if !cfg.Webhook.WorkerEnabled {
+		logger.Info("Webhook worker disabled (WEBHOOK_WORKER_ENABLED=false)")
+		return nil
+	}
+
+	worker := webhook.NewWorker(repo, webhook.WorkerConfig{
+		PollInterval: cfg.Webhook.PollInterval,
+		BatchSize:    cfg.Webhook.BatchSize,
+		Timeout:      cfg.Webhook.Timeout,
+		MaxAttempts:  cfg.Webhook.MaxAttempts,
+		Backoff:      outbox.Backoff{Initial: cfg.Webhook.InitialBackoff, Max: cfg.Webhook.MaxBackoff},
+	}, logger)
+	worker.Start()
+
+	logger.Info("Webhook worker started", zap.Int("max_attempts", cfg.Webhook.MaxAttempts))
+	return worker
+}
+
+// initNotificationWorker starts the customer notification worker, or returns nil when disabled.
+func initNotificationWorker(
+	cfg *config.Config, repo *postgres.SubscriptionRepository, logger *zap.Logger,
+) *notify.Worker {
+	if !cfg.Notification.Enabled {
+		logger.Info("Notifications disabled (NOTIFICATIONS_ENABLED=false)")
+		return nil
+	}
+
+	var email notify.Notifier = notify.NewLogNotifier(logger)
+	if cfg.Notification.SMTPHost != "" {
+		email = notify.NewSMTPNotifier(
+			cfg.Notification.SMTPHost, cfg.Notification.SMTPPort, cfg.Notification.SMTPFrom,
+			cfg.Notification.SMTPUsername, cfg.Notification.SMTPPassword,
+		)
+	} else {
+		logger.Warn("SMTP_HOST not set, email notifications will only be logged")
+	}
+	notifier := &notify.ChannelNotifier{
+		Email:   email,
+		Webhook: notify.NewWebhookNotifier(cfg.Notification.WebhookTimeout),
+	}
+
+	worker := notify.NewWorker(repo, notifier, notify.WorkerConfig{
+		PollInterval:  cfg.Notification.PollInterval,
+		BatchSize:     cfg.Notification.BatchSize,
+		Timeout:       cfg.Notification.WebhookTimeout,
+		MaxAttempts:   cfg.Notification.MaxAttempts,
+		Backoff:       outbox.Backoff{Initial: cfg.Notification.InitialBackoff, Max: cfg.Notification.MaxBackoff},
+		PublicBaseURL: cfg.Notification.PublicBaseURL,
+	}, logger)
+	worker.Start()
+
+	logger.Info("Notification worker started", zap.Int("max_attempts", cfg.Notification.MaxAttempts))
+	return worker
+}
+
+func setupServer(
+	cfg *config.Config,
+	logger *zap.Logger,
+	isShuttingDown *atomic.Bool,
+	readiness *health.Registry,
+	limiter *middleware.RateLimiter,
+	handlers webv1.Handlers,
+) *http.Server {
+	// Not gin.Default: its access log and panic dump print the raw query, which
+	// carries customer and unsubscribe tokens. LoggingMiddleware logs the path only.
+	r := gin.New()
+	// Resolve c.ClientIP() from X-Forwarded-For only when the peer is a trusted proxy
+	if err := r.SetTrustedProxies(cfg.RateLimit.TrustedProxies); err != nil {
+		logger.Warn("Invalid TRUSTED_PROXIES, trusting no proxy", zap.Error(err))
+		_ = r.SetTrustedProxies(nil)
+	}
+
+	r.Use(middleware.TracingMiddleware())
+	r.Use(middleware.LoggingMiddleware(logger))
+	r.Use(middleware.PrometheusMiddleware())
+	// After logging and metrics, so a recovered panic is still logged and counted as a 500
+	r.Use(middleware.RecoveryMiddleware(logger))
+	if cfg.Logging.BodyEnabled {
+		r.Use(middleware.BodyLoggingMiddleware(middleware.BodyLogConfig{
+			Routes:       cfg.Logging.BodyRoutes,
+			MaxBytes:     cfg.Logging.BodyMaxBytes,
+			ContentTypes: cfg.Logging.BodyContentTypes,
+			Redactor:     middleware.NewRedactor(cfg.Logging.RedactFields),
+		}))
+		logger.Warn("Request/response body logging enabled (LOG_BODY_ENABLED=true)",
+			zap.Strings("routes", cfg.Logging.BodyRoutes))
+	}
+	if limiter != nil {
+		r.Use(middleware.RateLimitMiddleware(limiter))
+	}
+
+	// Liveness: the process serves requests; dependencies are not checked
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+	r.GET("/ready", func(c *gin.Context) {
+		if isShuttingDown.Load() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+			return
+		}
+		report := readiness.Run(c.Request.Context())
+		if !report.Ready() {
+			c.JSON(http.StatusServiceUnavailable, report)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	webv1.RegisterRoutes(r, handlers)
+
+	return &http.Server{
+		Addr:              ":" + cfg.Service.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// newRateLimiter builds the public endpoint rate limiter, or returns nil when disabled.
+// Callers with a valid API key are limited per key instead of per client IP.
+func newRateLimiter(cfg *config.Config, authenticator *auth.Authenticator, logger *zap.Logger) *middleware.RateLimiter {
+	if !cfg.RateLimit.Enabled {
+		logger.Info("Rate limiting disabled (RATE_LIMIT_ENABLED=false)")
+		return nil
+	}
+
+	routes := make(map[string]middleware.RateLimit, len(cfg.RateLimit.Routes))
+	for path, limit := range cfg.RateLimit.Routes {
+		routes[path] = middleware.RateLimit{RPS: limit.RPS, Burst: limit.Burst}
+	}
+	rlCfg := middleware.RateLimiterConfig{Routes: routes}
+	if authenticator != nil {
+		rlCfg.APIKey = func(r *http.Request) (string, bool) {
+			p, ok := authenticator.LookupAPIKey(r.Header.Get(auth.APIKeyHeader))
+			if !ok {
+				return "", false
+			}
+			return p.Subject, true
+		}
+	}
+
+	logger.Info("Rate limiting enabled", zap.Int("routes", len(routes)))
+	return middleware.NewRateLimiter(rlCfg)
+}
+
+// setupGRPCServer builds the gRPC API server, or returns nil when disabled.
+// It shares the HTTP server's tracing, RED metrics, authentication and rate
+// limits via interceptors.
+func setupGRPCServer(
+	cfg *config.Config,
+	server *grpcv1.Server,
+	authenticator *auth.Authenticator,
+	limiter *middleware.RateLimiter,
+	logger *zap.Logger,
+) *grpc.Server {
+	if !cfg.GRPC.Enabled {
+		logger.Info("gRPC server disabled (GRPC_ENABLED=false)")
+		return nil
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		middleware.GRPCTracingInterceptor(),
+		middleware.GRPCPrometheusInterceptor(),
+		grpcv1.AuthInterceptor(authenticator, logger),
+	}
+	if limiter != nil {
+		interceptors = append(interceptors, middleware.GRPCRateLimitInterceptor(limiter, grpcv1.CallerSubject))
+	}
+	// Only internal callers may pin their reads to the primary, as on HTTP
+	interceptors = append(interceptors, middleware.GRPCReadConsistencyInterceptor(grpcv1.InternalMethods()...))
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	grpcv1.Register(s, server)
+	return s
+}
+
+// stopGRPCServer drains in-flight calls, forcing the stop when ctx expires.
+func stopGRPCServer(ctx context.Context, s *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
+
+func runGracefulShutdown(
+	cfg *config.Config,
+	srv *http.Server,
+	grpcSrv *grpc.Server,
+	telemetry otelProviders,
+	pool interface{ Close() },
+	workers []backgroundWorker,
+	logger *zap.Logger,
+	isShuttingDown *atomic.Bool,
+) {
+	go func() {
+		logger.Info("Starting shipping service", zap.String("port", cfg.Service.Port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	if grpcSrv != nil {
+		go func() {
+			lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+			if err != nil {
+				logger.Error("Failed to listen for gRPC", zap.Error(err))
+				return
+			}
+			logger.Info("Starting gRPC server", zap.String("port", cfg.GRPC.Port))
+			if err := grpcSrv.Serve(lis); err != nil {
+				logger.Error("gRPC server error", zap.Error(err))
+			}
+		}()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	<-ctx.Done()
+	logger.Info("Shutdown signal received")
+
+	isShuttingDown.Store(true)
+	drainDelay := cfg.GetReadinessDrainDelayDuration()
+	if drainDelay > 0 {
+		logger.Info("Readiness drain delay started", zap.Duration("delay", drainDelay))
+		time.Sleep(drainDelay)
+	}
+
+	shutdownTimeout := cfg.GetShutdownTimeoutDuration()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	logger.Info("Shutting down server...", zap.Duration("timeout", shutdownTimeout))
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server shutdown error", zap.Error(err))
+	} else {
+		logger.Info("HTTP server shutdown complete")
+	}
+
+	if grpcSrv != nil {
+		if err := stopGRPCServer(shutdownCtx, grpcSrv); err != nil {
+			logger.Error("gRPC server shutdown error", zap.Error(err))
+		} else {
+			logger.Info("gRPC server shutdown complete")
+		}
+	}
+
+	// Stop background workers before closing the pool they read from
+	for _, w := range workers {
+		w.stop()
+		logger.Info(w.name + " stopped")
+	}
+
+	pool.Close()
+	logger.Info("Database pools closed")
+
+	if telemetry.tracer != nil {
+		if err := telemetry.tracer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Tracer shutdown error", zap.Error(err))
+		} else {
+			logger.Info("Tracer shutdown complete")
+		}
+	}
+	if telemetry.meter != nil {
+		if err := telemetry.meter.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Meter provider shutdown error", zap.Error(err))
+		} else {
+			logger.Info("Meter provider shutdown complete")
+		}
+	}
+
+	middleware.StopProfiling()
+	logger.Info("Graceful shutdown complete")
+
+	// Last, so the shutdown logs above are exported too
+	if telemetry.logs != nil {
+		if err := telemetry.logs.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Logger provider shutdown error", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,5 @@
+-- V10__shipments_id_sequence.sql
+-- Move the shipments id sequence past the explicit ids inserted by the seed
+-- data, so shipments created over the API do not collide with them.
+
+SELECT setval(pg_get_serial_sequence('shipments', 'id'), COALESCE(MAX(id), 1)) FROM shipments;
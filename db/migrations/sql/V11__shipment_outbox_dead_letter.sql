@@ -0,0 +1,13 @@
+-- V11__shipment_outbox_dead_letter.sql
+-- Stop retrying outbox events after OUTBOX_MAX_ATTEMPTS failed deliveries
+
+-- Dead-lettered events are kept for inspection and are no longer claimed
+-- by the relay; ops re-queue them by clearing dead_lettered_at.
+ALTER TABLE shipment_outbox ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMP;
+
+-- Indexes
+DROP INDEX IF EXISTS idx_shipment_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_shipment_outbox_pending
+    ON shipment_outbox(next_attempt_at, id) WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_shipment_outbox_dead_lettered
+    ON shipment_outbox(dead_lettered_at) WHERE dead_lettered_at IS NOT NULL;
@@ -0,0 +1,24 @@
+-- V12__notification_deliveries.sql
+-- Per-subscription delivery queue for customer tracking notifications
+
+-- One row per (subscription, outbox event). The notification worker retries
+-- each row on its own, so one unreachable subscriber does not hold up the
+-- others or the outbox event; rows that exhaust their retries are
+-- dead-lettered in place.
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES tracking_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,               -- shipment_outbox.id
+    payload JSONB NOT NULL,                 -- shipment event payload
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    dead_lettered_at TIMESTAMP,
+    UNIQUE (subscription_id, event_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pending
+    ON notification_deliveries(next_attempt_at, id) WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
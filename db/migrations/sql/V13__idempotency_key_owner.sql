@@ -0,0 +1,7 @@
+-- V13__idempotency_key_owner.sql
+-- Tie an in-flight Idempotency-Key to the attempt that claimed it
+
+-- A fresh owner token is stored on every claim, including a takeover after
+-- locked_until ran out, so a timed-out attempt that finishes late cannot
+-- complete or release the key now held by the attempt that took it over.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS owner VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,32 @@
+-- V3__shipment_exceptions.sql
+-- Delivery exceptions reported by carriers and the tracking event history
+
+-- Tracking events: append-only history of what happened to a shipment
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    description TEXT,
+    occurred_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Delivery exceptions: address not found, damaged, held at customs, ...
+-- A shipment has at most one open (unresolved) exception at a time.
+CREATE TABLE IF NOT EXISTS shipment_exceptions (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    reason_code VARCHAR(50) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    resolution VARCHAR(100),
+    resolved_by VARCHAR(100)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_shipment_events_shipment ON shipment_events(shipment_id, occurred_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipment_exceptions_open
+    ON shipment_exceptions(shipment_id) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_shipment_exceptions_created ON shipment_exceptions(created_at)
+    WHERE resolved_at IS NULL;
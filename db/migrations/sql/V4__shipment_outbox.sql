@@ -0,0 +1,20 @@
+-- V4__shipment_outbox.sql
+-- Transactional outbox for shipment events consumed by other services
+
+-- Rows are written in the same transaction as the shipment change and
+-- delivered at-least-once by the outbox relay. Consumers dedupe on id.
+CREATE TABLE IF NOT EXISTS shipment_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_shipment_outbox_pending
+    ON shipment_outbox(next_attempt_at, id) WHERE delivered_at IS NULL;
@@ -0,0 +1,16 @@
+-- V5__tracking_subscriptions.sql
+-- Customer subscriptions to tracking updates (public track page)
+
+CREATE TABLE IF NOT EXISTS tracking_subscriptions (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,           -- 'email' | 'webhook'
+    target VARCHAR(2048) NOT NULL,          -- email address or webhook URL
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    unsubscribed_at TIMESTAMP
+);
+
+-- Indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tracking_subscriptions_active
+    ON tracking_subscriptions(shipment_id, channel, target) WHERE unsubscribed_at IS NULL;
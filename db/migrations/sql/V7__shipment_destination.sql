@@ -0,0 +1,4 @@
+-- V7__shipment_destination.sql
+-- Destination postal code, used to verify public tracking callers
+
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS destination_postal_code VARCHAR(20);
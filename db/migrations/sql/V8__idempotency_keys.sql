@@ -0,0 +1,21 @@
+-- V8__idempotency_keys.sql
+-- Idempotency-Key records for mutating internal endpoints
+
+-- One row per (scope, key). A row with NULL status_code is a request still in
+-- flight; locked_until lets another attempt take over if that request died.
+-- Rows past expires_at are reclaimed on reuse and purged periodically.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(255) NOT NULL,            -- caller + method + route
+    idempotency_key VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,          -- SHA-256 of method, path and body
+    status_code INTEGER,
+    response_headers JSONB,
+    response_body BYTEA,
+    locked_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
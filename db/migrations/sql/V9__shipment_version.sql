@@ -0,0 +1,6 @@
+-- V9__shipment_version.sql
+-- Row version for optimistic concurrency (ETag / If-Match on the HTTP API)
+
+-- Bumped by every change to a shipment's representation: status updates and
+-- opening or resolving a delivery exception.
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
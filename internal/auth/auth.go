@@ -0,0 +1,192 @@
+// Package auth authenticates service-to-service callers of the internal API.
+//
+// A caller presents either a JWT (RS256 or ES256) signed by a key in the
+// configured JWKS, as "Authorization: Bearer <token>", or a service API key in
+// the X-API-Key header. Both resolve to a Principal carrying the granted
+// scopes; the web layer checks the scope each route requires, and the gRPC
+// server the scope of each method.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// Scopes granted to internal callers.
+const (
+	ScopeShipmentsRead  = "shipments:read"  // Read shipments and exceptions
+	ScopeShipmentsWrite = "shipments:write" // Create shipments, update status, raise and resolve exceptions
+	ScopeWebhooksManage = "webhooks:manage" // Manage merchant webhooks and replay dead letters
+	ScopeLoggingManage  = "logging:manage"  // Read and change the runtime log level
+)
+
+// APIKeyHeader carries a service API key.
+const APIKeyHeader = "X-API-Key"
+
+// Authentication methods reported in Principal.Method.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+var (
+	// ErrNoCredentials indicates the request carries neither a bearer token nor an API key.
+	ErrNoCredentials = errors.New("no credentials")
+
+	// ErrInvalidCredentials indicates the token or API key was presented but is not valid.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// signatureAlgorithms are the only JWT algorithms accepted; symmetric and
+// "none" algorithms are rejected before any key is looked up.
+var signatureAlgorithms = []jose.SignatureAlgorithm{jose.RS256, jose.ES256}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string   // JWT "sub" or API key name
+	Method  string   // MethodJWT or MethodAPIKey
+	Scopes  []string // Granted scopes
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// APIKey is a service API key and the scopes it grants.
+type APIKey struct {
+	Name   string   `json:"name"`   // Calling service, reported as the principal subject
+	Key    string   `json:"key"`    // Secret value sent in X-API-Key
+	Scopes []string `json:"scopes"` // Granted scopes
+}
+
+// Config configures an Authenticator. Keys may be nil when only API keys are used.
+type Config struct {
+	Keys     KeySet   // JWT verification keys
+	Issuer   string   // Expected "iss" claim (optional)
+	Audience string   // Expected "aud" claim (optional)
+	APIKeys  []APIKey // Accepted service API keys
+}
+
+// Authenticator verifies request credentials.
+type Authenticator struct {
+	keys     KeySet
+	expected jwt.Expected
+	apiKeys  map[[sha256.Size]byte]APIKey
+	now      func() time.Time
+}
+
+// New creates an Authenticator.
+func New(cfg Config) *Authenticator {
+	a := &Authenticator{
+		keys:     cfg.Keys,
+		expected: jwt.Expected{Issuer: cfg.Issuer},
+		apiKeys:  make(map[[sha256.Size]byte]APIKey, len(cfg.APIKeys)),
+		now:      time.Now,
+	}
+	if cfg.Audience != "" {
+		a.expected.AnyAudience = jwt.Audience{cfg.Audience}
+	}
+	// Keys are indexed by hash so lookups do not compare secrets byte by byte.
+	for _, k := range cfg.APIKeys {
+		a.apiKeys[sha256.Sum256([]byte(k.Key))] = k
+	}
+	return a
+}
+
+// Authenticate resolves the caller of r. A bearer token takes precedence over an API key.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	return a.AuthenticateCredentials(r.Context(), r.Header.Get("Authorization"), r.Header.Get(APIKeyHeader))
+}
+
+// AuthenticateCredentials resolves a caller from the values of the
+// Authorization and X-API-Key headers, for transports that carry them
+// elsewhere, such as gRPC metadata.
+func (a *Authenticator) AuthenticateCredentials(ctx context.Context, authorization, apiKey string) (*Principal, error) {
+	if token, ok := bearerToken(authorization); ok {
+		return a.verifyJWT(ctx, token)
+	}
+	if apiKey != "" {
+		return a.verifyAPIKey(apiKey)
+	}
+	return nil, ErrNoCredentials
+}
+
+func (a *Authenticator) verifyJWT(ctx context.Context, raw string) (*Principal, error) {
+	if a.keys == nil {
+		return nil, fmt.Errorf("bearer token: no JWKS configured: %w", ErrInvalidCredentials)
+	}
+	token, err := jwt.ParseSigned(raw, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("parse token: %v: %w", err, ErrInvalidCredentials)
+	}
+
+	key, err := a.keys.Key(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve signing key: %v: %w", err, ErrInvalidCredentials)
+	}
+
+	var claims jwt.Claims
+	var extra scopeClaims
+	if err := token.Claims(key.Key, &claims, &extra); err != nil {
+		return nil, fmt.Errorf("verify token: %v: %w", err, ErrInvalidCredentials)
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("verify token: missing exp claim: %w", ErrInvalidCredentials)
+	}
+	if err := claims.ValidateWithLeeway(a.expected.WithTime(a.now()), jwt.DefaultLeeway); err != nil {
+		return nil, fmt.Errorf("validate claims: %v: %w", err, ErrInvalidCredentials)
+	}
+
+	return &Principal{Subject: claims.Subject, Method: MethodJWT, Scopes: extra.scopes()}, nil
+}
+
+func (a *Authenticator) verifyAPIKey(key string) (*Principal, error) {
+	p, ok := a.LookupAPIKey(key)
+	if !ok {
+		return nil, fmt.Errorf("api key: %w", ErrInvalidCredentials)
+	}
+	return p, nil
+}
+
+// LookupAPIKey returns the principal of a configured API key. Routes without
+// authentication use it to recognise partners, e.g. for rate limiting.
+func (a *Authenticator) LookupAPIKey(key string) (*Principal, bool) {
+	if key == "" {
+		return nil, false
+	}
+	k, ok := a.apiKeys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, false
+	}
+	return &Principal{Subject: k.Name, Method: MethodAPIKey, Scopes: k.Scopes}, true
+}
+
+// scopeClaims accepts both the OAuth 2.0 "scope" string (RFC 8693) and the
+// "scp" array some identity providers issue.
+type scopeClaims struct {
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+func (s scopeClaims) scopes() []string {
+	return append(strings.Fields(s.Scope), s.Scp...)
+}
+
+func bearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
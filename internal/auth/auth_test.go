@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+type testKey struct {
+	kid     string
+	alg     jose.SignatureAlgorithm
+	private crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, alg: jose.RS256, private: k}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, alg: jose.ES256, private: k}
+}
+
+func (k testKey) jwk() jose.JSONWebKey {
+	return jose.JSONWebKey{Key: k.private.Public(), KeyID: k.kid, Algorithm: string(k.alg), Use: "sig"}
+}
+
+func (k testKey) sign(t *testing.T, claims jwt.Claims, scope string) string {
+	t.Helper()
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: k.alg, Key: k.private},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), k.kid),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Claims(map[string]any{"scope": scope}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func validClaims(now time.Time) jwt.Claims {
+	return jwt.Claims{
+		Subject:  "order-service",
+		Issuer:   "https://idp.example",
+		Audience: jwt.Audience{"shipping-service"},
+		Expiry:   jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Now()
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	otherKey := newRSAKey(t, "rsa-1") // same kid, not in the key set
+
+	a := New(Config{
+		Keys:     NewStaticKeySet(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{rsaKey.jwk(), ecKey.jwk()}}),
+		Issuer:   "https://idp.example",
+		Audience: "shipping-service",
+		APIKeys:  []APIKey{{Name: "carrier-sync", Key: "s3cret", Scopes: []string{ScopeShipmentsWrite}}},
+	})
+
+	expired := validClaims(now.Add(-time.Hour))
+	wrongIssuer := validClaims(now)
+	wrongIssuer.Issuer = "https://evil.example"
+	wrongAudience := validClaims(now)
+	wrongAudience.Audience = jwt.Audience{"billing-service"}
+	noExpiry := validClaims(now)
+	noExpiry.Expiry = nil
+
+	tests := []struct {
+		name        string
+		header      string
+		value       string
+		wantErr     error
+		wantSubject string
+		wantScope   string
+	}{
+		{"RS256 token", "Authorization", "Bearer " + rsaKey.sign(t, validClaims(now), "shipments:read shipments:write"), nil, "order-service", ScopeShipmentsWrite},
+		{"ES256 token", "Authorization", "Bearer " + ecKey.sign(t, validClaims(now), "shipments:read"), nil, "order-service", ScopeShipmentsRead},
+		{"API key", APIKeyHeader, "s3cret", nil, "carrier-sync", ScopeShipmentsWrite},
+		{"no credentials", "", "", ErrNoCredentials, "", ""},
+		{"wrong API key", APIKeyHeader, "guess", ErrInvalidCredentials, "", ""},
+		{"basic auth", "Authorization", "Basic dXNlcjpwYXNz", ErrNoCredentials, "", ""},
+		{"malformed token", "Authorization", "Bearer not-a-jwt", ErrInvalidCredentials, "", ""},
+		{"expired", "Authorization", "Bearer " + rsaKey.sign(t, expired, "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"wrong issuer", "Authorization", "Bearer " + rsaKey.sign(t, wrongIssuer, "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"wrong audience", "Authorization", "Bearer " + rsaKey.sign(t, wrongAudience, "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"missing exp", "Authorization", "Bearer " + rsaKey.sign(t, noExpiry, "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"untrusted signer", "Authorization", "Bearer " + otherKey.sign(t, validClaims(now), "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"unknown kid", "Authorization", "Bearer " + newECKey(t, "ec-9").sign(t, validClaims(now), "shipments:read"), ErrInvalidCredentials, "", ""},
+		{"HS256 rejected", "Authorization", "Bearer " + hs256Token(t, validClaims(now)), ErrInvalidCredentials, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/shipping/v1/internal/exceptions", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+
+			p, err := a.Authenticate(r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != tt.wantSubject || !p.HasScope(tt.wantScope) {
+				t.Errorf("principal = %+v, want subject %q with scope %q", p, tt.wantSubject, tt.wantScope)
+			}
+		})
+	}
+}
+
+func hs256Token(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRemoteKeySetRefetchesOnUnknownKid(t *testing.T) {
+	first := newRSAKey(t, "k1")
+	rotated := newECKey(t, "k2")
+
+	var served atomic.Pointer[jose.JSONWebKeySet]
+	served.Store(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{first.jwk()}})
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(served.Load())
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := NewRemoteKeySet(srv.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, "k1"); err != nil {
+		t.Fatalf("Key(k1): %v", err)
+	}
+
+	// The identity provider rotates keys; a token with the new kid arrives.
+	served.Store(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{first.jwk(), rotated.jwk()}})
+	if _, err := keys.Key(ctx, "k2"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(k2) right after a fetch: err = %v, want ErrUnknownKey (refetch is rate limited)", err)
+	}
+
+	now = now.Add(jwksMinRefetch)
+	if _, err := keys.Key(ctx, "k2"); err != nil {
+		t.Fatalf("Key(k2) after rotation: %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestRemoteKeySetServesStaleKeysDuringRefresh(t *testing.T) {
+	key := newRSAKey(t, "k1")
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.jwk()}}
+
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release // the identity provider hangs on the refresh
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var clock atomic.Int64
+	clock.Store(time.Now().UnixNano())
+	keys := NewRemoteKeySet(srv.URL, time.Minute)
+	keys.now = func() time.Time { return time.Unix(0, clock.Load()) }
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, "k1"); err != nil {
+		t.Fatalf("Key(k1): %v", err)
+	}
+
+	clock.Add(int64(2 * time.Minute))
+	done := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := keys.Key(ctx, "k1")
+			done <- err
+		}()
+	}
+	for range 2 {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Key(k1) with expired keys: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Key(k1) blocked on the refresh instead of serving the last good keys")
+		}
+	}
+	if got := fetches.Load(); got > 2 {
+		t.Errorf("fetches = %d, want one refresh in flight", got)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	key := newECKey(t, "ec-1")
+
+	// A private JWK is accepted but only its public half is kept.
+	private := jose.JSONWebKey{Key: key.private, KeyID: key.kid, Algorithm: string(key.alg)}
+	symmetric := jose.JSONWebKey{Key: []byte("0123456789abcdef"), KeyID: "hmac", Algorithm: string(jose.HS256)}
+	jwksPath := writeJSON(t, dir, "jwks.json", jose.JSONWebKeySet{Keys: []jose.JSONWebKey{private, symmetric}})
+
+	keys, err := LoadJWKSFile(jwksPath)
+	if err != nil {
+		t.Fatalf("LoadJWKSFile: %v", err)
+	}
+	got, err := keys.Key(context.Background(), "ec-1")
+	if err != nil || !got.IsPublic() {
+		t.Errorf("Key(ec-1) = %v, %v; want the public key", got, err)
+	}
+	if _, err := keys.Key(context.Background(), "hmac"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("symmetric key was kept: err = %v", err)
+	}
+
+	onlySymmetric := writeJSON(t, dir, "hmac.json", jose.JSONWebKeySet{Keys: []jose.JSONWebKey{symmetric}})
+	if _, err := LoadJWKSFile(onlySymmetric); err == nil {
+		t.Error("LoadJWKSFile accepted a key set without public keys")
+	}
+
+	apiKeys := writeJSON(t, dir, "keys.json", []APIKey{{Name: "ops", Key: "k", Scopes: []string{ScopeWebhooksManage}}})
+	if loaded, err := LoadAPIKeys(apiKeys); err != nil || len(loaded) != 1 || loaded[0].Name != "ops" {
+		t.Errorf("LoadAPIKeys = %+v, %v", loaded, err)
+	}
+	unnamed := writeJSON(t, dir, "bad.json", []APIKey{{Key: "k"}})
+	if _, err := LoadAPIKeys(unnamed); err == nil {
+		t.Error("LoadAPIKeys accepted a key without a name")
+	}
+}
+
+func writeJSON(t *testing.T, dir, name string, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrUnknownKey indicates no verification key matches the token's key ID.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet resolves JWT verification keys by key ID ("kid").
+type KeySet interface {
+	Key(ctx context.Context, kid string) (*jose.JSONWebKey, error)
+}
+
+// StaticKeySet is a fixed JWKS, e.g. mounted from a Kubernetes secret.
+type StaticKeySet struct {
+	set jose.JSONWebKeySet
+}
+
+// NewStaticKeySet wraps set, keeping only the public part of each key.
+func NewStaticKeySet(set jose.JSONWebKeySet) *StaticKeySet {
+	return &StaticKeySet{set: publicKeys(set)}
+}
+
+// LoadJWKSFile reads a JWKS document from path.
+func LoadJWKSFile(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks file %q: %w", path, err)
+	}
+	set, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwks file %q: %w", path, err)
+	}
+	return NewStaticKeySet(set), nil
+}
+
+// Key implements KeySet.
+func (s *StaticKeySet) Key(_ context.Context, kid string) (*jose.JSONWebKey, error) {
+	return findKey(s.set, kid)
+}
+
+// RemoteKeySet fetches a JWKS from the identity provider's URL. Keys are
+// refetched after the refresh interval, and early when a token names an
+// unknown key ID (key rotation), at most once per minRefetch.
+type RemoteKeySet struct {
+	url        string
+	client     *http.Client
+	refresh    time.Duration
+	minRefetch time.Duration
+	now        func() time.Time
+	group      singleflight.Group // one fetch in flight at a time
+
+	mu        sync.Mutex
+	set       jose.JSONWebKeySet
+	fetched   time.Time // last successful fetch
+	attempted time.Time // last fetch, successful or not
+}
+
+const (
+	jwksFetchTimeout = 5 * time.Second
+	jwksMinRefetch   = 30 * time.Second
+	jwksFlight       = "jwks"
+)
+
+// NewRemoteKeySet creates a key set fetched lazily from url.
+func NewRemoteKeySet(url string, refresh time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:        url,
+		client:     &http.Client{Timeout: jwksFetchTimeout},
+		refresh:    refresh,
+		minRefetch: min(jwksMinRefetch, refresh),
+		now:        time.Now,
+	}
+}
+
+// Refresh fetches the key set now. Call it at startup to surface a bad URL early.
+func (s *RemoteKeySet) Refresh(ctx context.Context) error {
+	return s.refetch(ctx)
+}
+
+// Key implements KeySet. Expired keys keep being served while one background
+// fetch refreshes them, so a slow identity provider does not stall token
+// verification, and a failed refetch keeps the last good keys. Only the first
+// lookup and a lookup for an unknown key ID wait for the fetch.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	set, fetched, attempted := s.snapshot()
+	if fetched.IsZero() {
+		if err := s.refetch(ctx); err != nil {
+			return nil, err
+		}
+		set, _, attempted = s.snapshot()
+	} else if now := s.now(); now.Sub(fetched) >= s.refresh && now.Sub(attempted) >= s.minRefetch {
+		s.group.DoChan(jwksFlight, func() (any, error) {
+			return nil, s.fetch(context.WithoutCancel(ctx))
+		})
+	}
+
+	key, err := findKey(set, kid)
+	if errors.Is(err, ErrUnknownKey) && s.now().Sub(attempted) >= s.minRefetch {
+		if err := s.refetch(ctx); err != nil {
+			return nil, err
+		}
+		set, _, _ = s.snapshot()
+		return findKey(set, kid)
+	}
+	return key, err
+}
+
+func (s *RemoteKeySet) snapshot() (set jose.JSONWebKeySet, fetched, attempted time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set, s.fetched, s.attempted
+}
+
+// refetch fetches the keys and waits for the result, joining a fetch already
+// in flight. The fetch outlives ctx so joined callers are not failed by the
+// first caller going away; the client timeout bounds it.
+func (s *RemoteKeySet) refetch(ctx context.Context) error {
+	_, err, _ := s.group.Do(jwksFlight, func() (any, error) {
+		return nil, s.fetch(context.WithoutCancel(ctx))
+	})
+	return err
+}
+
+// fetch downloads the keys without holding s.mu and replaces the cached set on success.
+func (s *RemoteKeySet) fetch(ctx context.Context) error {
+	set, err := s.download(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempted = s.now()
+	if err != nil {
+		return err
+	}
+	s.set = set
+	s.fetched = s.attempted
+	return nil
+}
+
+func (s *RemoteKeySet) download(ctx context.Context) (jose.JSONWebKeySet, error) {
+	var set jose.JSONWebKeySet
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return set, fmt.Errorf("build jwks request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return set, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return set, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return set, fmt.Errorf("read jwks: %w", err)
+	}
+	set, err = parseJWKS(data)
+	if err != nil {
+		return set, fmt.Errorf("jwks %q: %w", s.url, err)
+	}
+	return set, nil
+}
+
+func parseJWKS(data []byte) (jose.JSONWebKeySet, error) {
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return set, fmt.Errorf("parse: %w", err)
+	}
+	set = publicKeys(set)
+	if len(set.Keys) == 0 {
+		return set, errors.New("no RSA or EC public keys")
+	}
+	return set, nil
+}
+
+// publicKeys drops private material and symmetric keys, which must never verify tokens here.
+func publicKeys(set jose.JSONWebKeySet) jose.JSONWebKeySet {
+	var out jose.JSONWebKeySet
+	for _, k := range set.Keys {
+		if pub := k.Public(); pub.Valid() {
+			out.Keys = append(out.Keys, pub)
+		}
+	}
+	return out
+}
+
+// findKey returns the key with kid. A token without a kid is accepted only
+// when the set holds exactly one key.
+func findKey(set jose.JSONWebKeySet, kid string) (*jose.JSONWebKey, error) {
+	if kid == "" {
+		if len(set.Keys) == 1 {
+			return &set.Keys[0], nil
+		}
+		return nil, fmt.Errorf("token has no kid: %w", ErrUnknownKey)
+	}
+	keys := set.Key(kid)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("kid %q: %w", kid, ErrUnknownKey)
+	}
+	return &keys[0], nil
+}
+
+// LoadAPIKeys reads service API keys from a JSON file:
+//
+//	[{"name": "order-service", "key": "...", "scopes": ["shipments:read", "shipments:write"]}]
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read api keys file %q: %w", path, err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse api keys file %q: %w", path, err)
+	}
+	for i, k := range keys {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("api keys file %q: entry %d needs a name and a key", path, i)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaPingTimeout bounds one replica health check.
+const replicaPingTimeout = 2 * time.Second
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that Cluster.Reader returns the primary. Use it
+// when a read must see the caller's own recent writes, which a lagging
+// replica may not have yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryRequested reports whether ctx was marked by WithPrimary.
+func PrimaryRequested(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// replica is a read-only pool and the result of its last health check.
+type replica struct {
+	host    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// Cluster routes queries between the primary pool and optional read replicas
+// (DB_READ_HOSTS). Writes and transactions always use Primary; read-only
+// queries that tolerate replication lag use Reader.
+//
+// Replicas are health-checked in the background; Reader skips unhealthy ones
+// and falls back to the primary when none is healthy.
+type Cluster struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     atomic.Uint64
+
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewCluster builds a cluster over the primary and replica pools, keyed by
+// replica host. Replicas take reads once a health check has passed.
+func NewCluster(primary *pgxpool.Pool, replicas map[string]*pgxpool.Pool) *Cluster {
+	c := &Cluster{primary: primary, stop: make(chan struct{}), done: make(chan struct{})}
+	for host, pool := range replicas {
+		c.replicas = append(c.replicas, &replica{host: host, pool: pool})
+		replicaUp.WithLabelValues(host).Set(0)
+	}
+	return c
+}
+
+// Primary returns the read-write pool.
+func (c *Cluster) Primary() *pgxpool.Pool {
+	return c.primary
+}
+
+// Reader returns a healthy replica in round-robin order, or the primary when
+// there are no healthy replicas or ctx asks for it with WithPrimary.
+func (c *Cluster) Reader(ctx context.Context) *pgxpool.Pool {
+	if len(c.replicas) == 0 {
+		return c.primary
+	}
+	if PrimaryRequested(ctx) {
+		reads.WithLabelValues("primary", "requested").Inc()
+		return c.primary
+	}
+
+	start := c.next.Add(1)
+	for i := range uint64(len(c.replicas)) {
+		r := c.replicas[(start+i)%uint64(len(c.replicas))]
+		if r.healthy.Load() {
+			reads.WithLabelValues("replica", "").Inc()
+			return r.pool
+		}
+	}
+	reads.WithLabelValues("primary", "replicas_unhealthy").Inc()
+	return c.primary
+}
+
+// Ping checks that the primary is reachable.
+func (c *Cluster) Ping(ctx context.Context) error {
+	return c.primary.Ping(ctx)
+}
+
+// ReplicaStatus returns an error when replicas are configured but none is
+// healthy, so reads have fallen back to the primary.
+func (c *Cluster) ReplicaStatus(context.Context) error {
+	healthy := 0
+	for _, r := range c.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	if len(c.replicas) > 0 && healthy == 0 {
+		return fmt.Errorf("none of %d read replicas is healthy, reads use the primary", len(c.replicas))
+	}
+	return nil
+}
+
+// HasReplicas reports whether read replicas are configured.
+func (c *Cluster) HasReplicas() bool {
+	return len(c.replicas) > 0
+}
+
+// StartHealthChecks pings every replica now and then each interval until
+// Stop. It is a no-op without replicas.
+func (c *Cluster) StartHealthChecks(interval time.Duration) {
+	if !c.started.CompareAndSwap(false, true) {
+		return
+	}
+	if len(c.replicas) == 0 {
+		close(c.done)
+		return
+	}
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.checkReplicas()
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *Cluster) checkReplicas() {
+	var wg sync.WaitGroup
+	for _, r := range c.replicas {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+			defer cancel()
+			healthy := r.pool.Ping(ctx) == nil
+			r.healthy.Store(healthy)
+			if healthy {
+				replicaUp.WithLabelValues(r.host).Set(1)
+			} else {
+				replicaUp.WithLabelValues(r.host).Set(0)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Stop ends the health checks started by StartHealthChecks and waits for them.
+func (c *Cluster) Stop() {
+	if !c.started.Load() {
+		return
+	}
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// Close closes the replica pools and then the primary.
+func (c *Cluster) Close() {
+	for _, r := range c.replicas {
+		r.pool.Close()
+	}
+	c.primary.Close()
+}
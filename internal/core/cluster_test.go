@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestClusterReader(t *testing.T) {
+	primary, replicaA, replicaB := new(pgxpool.Pool), new(pgxpool.Pool), new(pgxpool.Pool)
+	names := map[*pgxpool.Pool]string{primary: "primary", replicaA: "a", replicaB: "b"}
+
+	tests := []struct {
+		name     string
+		replicas map[string]*pgxpool.Pool
+		healthy  map[string]bool
+		ctx      context.Context
+		want     []string // Pools returned by successive calls
+	}{
+		{
+			name: "no replicas",
+			ctx:  context.Background(),
+			want: []string{"primary", "primary"},
+		},
+		{
+			name:     "round robin over healthy replicas",
+			replicas: map[string]*pgxpool.Pool{"a": replicaA, "b": replicaB},
+			healthy:  map[string]bool{"a": true, "b": true},
+			ctx:      context.Background(),
+			want:     []string{"x", "y", "x", "y"},
+		},
+		{
+			name:     "skips unhealthy replica",
+			replicas: map[string]*pgxpool.Pool{"a": replicaA, "b": replicaB},
+			healthy:  map[string]bool{"b": true},
+			ctx:      context.Background(),
+			want:     []string{"b", "b", "b"},
+		},
+		{
+			name:     "falls back to primary when none is healthy",
+			replicas: map[string]*pgxpool.Pool{"a": replicaA, "b": replicaB},
+			ctx:      context.Background(),
+			want:     []string{"primary", "primary"},
+		},
+		{
+			name:     "read your writes",
+			replicas: map[string]*pgxpool.Pool{"a": replicaA, "b": replicaB},
+			healthy:  map[string]bool{"a": true, "b": true},
+			ctx:      WithPrimary(context.Background()),
+			want:     []string{"primary", "primary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCluster(primary, tt.replicas)
+			for _, r := range c.replicas {
+				r.healthy.Store(tt.healthy[r.host])
+			}
+
+			var got []string
+			for range tt.want {
+				got = append(got, names[c.Reader(tt.ctx)])
+			}
+			// "x" and "y" stand for two distinct replicas, in either order.
+			if tt.want[0] == "x" {
+				for i := range got {
+					if got[i] == "primary" || got[i] == got[(i+1)%len(got)] {
+						t.Fatalf("Reader() = %v, want alternating replicas", got)
+					}
+				}
+				return
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Reader() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterStopWithoutStart(t *testing.T) {
+	c := NewCluster(new(pgxpool.Pool), nil)
+	c.Stop() // Must not block
+	c.StartHealthChecks(0)
+	c.Stop()
+}
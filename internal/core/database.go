@@ -4,101 +4,92 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
-	"os"
-	"strconv"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-)
 
-// DatabaseConfig holds database connection configuration
-type DatabaseConfig struct {
-	Host           string // DB_HOST - PostgreSQL host
-	Port           string // DB_PORT - PostgreSQL port (default: 5432)
-	Name           string // DB_NAME - Database name
-	User           string // DB_USER - Database user
-	Password       string // DB_PASSWORD - Database password
-	SSLMode        string // DB_SSLMODE - SSL mode
-	MaxConnections int    // DB_POOL_MAX_CONNECTIONS - Max pool connections
-}
+	"github.com/duynhne/shipping-service/config"
+)
 
 var globalPool *pgxpool.Pool
 
-// LoadConfig loads database configuration from environment variables.
-func LoadConfig() (*DatabaseConfig, error) {
-	cfg := &DatabaseConfig{
-		Host:           getEnv("DB_HOST", ""),
-		Port:           getEnv("DB_PORT", "5432"),
-		Name:           getEnv("DB_NAME", ""),
-		User:           getEnv("DB_USER", ""),
-		Password:       getEnv("DB_PASSWORD", ""),
-		SSLMode:        getEnv("DB_SSLMODE", "disable"),
-		MaxConnections: getEnvInt("DB_POOL_MAX_CONNECTIONS", 25),
-	}
-
-	if cfg.Host == "" {
+// Connect establishes the primary connection pool, and a pool per read
+// replica when DB_READ_HOSTS is set, using pgx/v5.
+// pgx is used instead of lib/pq for PgBouncer/PgCat compatibility.
+//
+// The primary must answer a ping. Replicas may be down at startup: they only
+// take reads once a health check has passed. Health checks run every
+// DB_READ_HEALTH_INTERVAL until Cluster.Stop.
+func Connect(ctx context.Context, cfg *config.Config) (*Cluster, error) {
+	dbCfg := &cfg.Database
+	if dbCfg.Host == "" {
 		return nil, errors.New("DB_HOST environment variable is required")
 	}
-	if cfg.Name == "" {
-		return nil, errors.New("DB_NAME environment variable is required")
-	}
-	if cfg.User == "" {
-		return nil, errors.New("DB_USER environment variable is required")
+
+	pool, err := newPool(ctx, dbCfg, dbCfg.BuildDSN())
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Password == "" {
-		return nil, errors.New("DB_PASSWORD environment variable is required")
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return cfg, nil
-}
+	replicas := make(map[string]*pgxpool.Pool, len(dbCfg.ReadHosts))
+	for _, hostPort := range dbCfg.ReplicaHostPorts() {
+		replica, err := newPool(ctx, dbCfg, dbCfg.BuildHostDSN(hostPort))
+		if err != nil {
+			for _, r := range replicas {
+				r.Close()
+			}
+			pool.Close()
+			return nil, fmt.Errorf("read replica %s: %w", hostPort, err)
+		}
+		replicas[hostPort] = replica
+	}
 
-// BuildDSN constructs PostgreSQL connection string (DSN) from config.
-func (c *DatabaseConfig) BuildDSN() string {
-	hostPort := net.JoinHostPort(c.Host, c.Port)
-	return fmt.Sprintf("postgresql://%s:%s@%s/%s?sslmode=%s&pool_max_conns=%d",
-		c.User, c.Password, hostPort, c.Name, c.SSLMode, c.MaxConnections,
-	)
+	globalPool = pool
+	cluster := NewCluster(pool, replicas)
+	cluster.StartHealthChecks(dbCfg.ReadHealthInterval)
+	return cluster, nil
 }
 
-// Connect establishes database connection pool using pgx/v5.
-// pgx is used instead of lib/pq for PgBouncer/PgCat compatibility.
+// newPool creates a pool for one server. Connections are opened lazily.
 //
-// IMPORTANT: We use SimpleProtocol mode and disable statement caching to work correctly
-// with transaction-mode connection poolers (PgCat/PgBouncer). Without this, you may see:
-//   "prepared statement stmtcache_* does not exist"
-func Connect(ctx context.Context) (*pgxpool.Pool, error) {
-	cfg, err := LoadConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load database config: %w", err)
-	}
-
+// Behind a transaction-mode pooler (DB_POOLER_TYPE with DB_POOL_MODE other
+// than session) we use SimpleProtocol mode and disable statement caching,
+// since consecutive statements may run on different server connections.
+// Without this, you may see:
+//
+//	"prepared statement stmtcache_* does not exist"
+//
+// Direct connections keep pgx's default of cached prepared statements.
+func newPool(ctx context.Context, cfg *config.DatabaseConfig, dsn string) (*pgxpool.Pool, error) {
 	// Parse DSN into pool config
-	poolCfg, err := pgxpool.ParseConfig(cfg.BuildDSN())
+	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
-
-	// Configure for transaction-mode poolers (PgCat/PgBouncer):
-	// - Use simple protocol to avoid server-side prepared statements
-	// - Disable statement cache (prepared statements are connection-scoped)
-	// - Disable description cache
-	poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
-	poolCfg.ConnConfig.StatementCacheCapacity = 0
-	poolCfg.ConnConfig.DescriptionCacheCapacity = 0
+	poolCfg.MaxConns = int32(cfg.MaxConnections)
+	poolCfg.MinConns = int32(cfg.MinConnections)
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolCfg.ConnConfig.Tracer = queryTracer{}
+
+	if cfg.TransactionPooler() {
+		// - Use simple protocol to avoid server-side prepared statements
+		// - Disable statement cache (prepared statements are connection-scoped)
+		// - Disable description cache
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+		poolCfg.ConnConfig.StatementCacheCapacity = 0
+		poolCfg.ConnConfig.DescriptionCacheCapacity = 0
+	}
 
 	// Create connection pool with the configured settings
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
-
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	globalPool = pool
 	return pool, nil
 }
 
@@ -113,19 +104,3 @@ func GetPool() *pgxpool.Pool {
 func GetDB() *pgxpool.Pool {
 	return globalPool
 }
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvInt(key string, defaultValue int) int {
-	if val := os.Getenv(key); val != "" {
-		if intVal, err := strconv.Atoi(val); err == nil {
-			return intVal
-		}
-	}
-	return defaultValue
-}
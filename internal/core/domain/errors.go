@@ -4,3 +4,34 @@ import "errors"
 
 // ErrShipmentNotFound indicates that the shipment could not be found.
 var ErrShipmentNotFound = errors.New("shipment not found")
+
+// ErrExceptionNotFound indicates that the delivery exception could not be found.
+var ErrExceptionNotFound = errors.New("shipment exception not found")
+
+// ErrExceptionAlreadyResolved indicates that the delivery exception was already resolved.
+var ErrExceptionAlreadyResolved = errors.New("shipment exception already resolved")
+
+// ErrOpenExceptionExists indicates that the shipment already has an unresolved exception.
+var ErrOpenExceptionExists = errors.New("shipment already has an open exception")
+
+// ErrDuplicateTrackingNumber indicates that a shipment with the tracking number already exists.
+var ErrDuplicateTrackingNumber = errors.New("duplicate tracking number")
+
+// ErrInvalidStatusTransition indicates that the shipment cannot move to the requested status.
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// ErrSubscriptionNotFound indicates that no active subscription matches the unsubscribe token.
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// ErrWebhookSubscriptionNotFound indicates that the webhook subscription could not be found.
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// ErrDeadLetterNotFound indicates that no unreplayed dead letter matches the ID.
+var ErrDeadLetterNotFound = errors.New("webhook dead letter not found")
+
+// ErrWebhookSubscriptionInactive indicates that the webhook subscription was deleted,
+// so its deliveries are no longer sent.
+var ErrWebhookSubscriptionInactive = errors.New("webhook subscription inactive")
+
+// ErrVersionMismatch indicates that the shipment changed since the version the caller expected.
+var ErrVersionMismatch = errors.New("shipment version mismatch")
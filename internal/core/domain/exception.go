@@ -0,0 +1,54 @@
+package domain
+
+// Exception reason codes reported by carriers.
+const (
+	ExceptionAddressNotFound      = "address_not_found"
+	ExceptionDamaged              = "damaged"
+	ExceptionHeldAtCustoms        = "held_at_customs"
+	ExceptionRecipientUnavailable = "recipient_unavailable"
+	ExceptionLost                 = "lost"
+	ExceptionWeatherDelay         = "weather_delay"
+)
+
+// Tracking event types recorded in shipment_events.
+const (
+	EventCreated           = "created"
+	EventStatusChanged     = "status_changed"
+	EventExceptionOpened   = "exception_opened"
+	EventExceptionResolved = "exception_resolved"
+)
+
+// ShipmentException is a delivery exception sub-state of a shipment.
+// ResolvedAt is nil while the exception is open.
+type ShipmentException struct {
+	ID             int     `json:"id"`
+	ShipmentID     int     `json:"shipment_id"`
+	TrackingNumber string  `json:"tracking_number,omitempty"` // Unset when embedded in a Shipment
+	ReasonCode     string  `json:"reason_code"`
+	Description    string  `json:"description,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	ResolvedAt     *string `json:"resolved_at,omitempty"`
+	Resolution     string  `json:"resolution,omitempty"`
+	ResolvedBy     string  `json:"resolved_by,omitempty"`
+}
+
+// TrackingEvent is a single entry in a shipment's tracking history.
+type TrackingEvent struct {
+	ID             int    `json:"id"`
+	ShipmentID     int    `json:"shipment_id"`
+	TrackingNumber string `json:"tracking_number"`
+	EventType      string `json:"event_type"`
+	Status         string `json:"status"`
+	Description    string `json:"description,omitempty"`
+	OccurredAt     string `json:"occurred_at"`
+}
+
+type RaiseExceptionRequest struct {
+	ReasonCode  string `json:"reason_code" binding:"required"`
+	Description string `json:"description"`
+}
+
+type ResolveExceptionRequest struct {
+	Resolution string `json:"resolution" binding:"required"`
+	ResolvedBy string `json:"resolved_by"`
+}
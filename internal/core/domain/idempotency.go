@@ -0,0 +1,15 @@
+package domain
+
+// IdempotencyRecord is a request stored under an Idempotency-Key and, once
+// that request completed, its response.
+type IdempotencyRecord struct {
+	Fingerprint string            // SHA-256 of the original request
+	StatusCode  int               // 0 while the original request is in flight
+	Header      map[string]string // Replayed response headers
+	Body        []byte
+}
+
+// InFlight reports whether the original request has not completed yet.
+func (r *IdempotencyRecord) InFlight() bool {
+	return r.StatusCode == 0
+}
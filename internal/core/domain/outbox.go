@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outbox event types published to other services.
+const (
+	OutboxShipmentCreated       = "shipment.created"
+	OutboxShipmentStatusChanged = "shipment.status_changed"
+)
+
+// OutboxEvent is a row of shipment_outbox awaiting (or past) delivery.
+type OutboxEvent struct {
+	ID         int64           `json:"id"`
+	ShipmentID int             `json:"shipment_id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ShipmentEventPayload is the payload of shipment.* outbox events.
+type ShipmentEventPayload struct {
+	ShipmentID     int    `json:"shipment_id"`
+	OrderID        int    `json:"order_id"`
+	TrackingNumber string `json:"tracking_number"`
+	Carrier        string `json:"carrier,omitempty"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	Status         string `json:"status"`
+	Description    string `json:"description,omitempty"`
+	OccurredAt     string `json:"occurred_at"`
+}
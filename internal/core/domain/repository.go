@@ -1,9 +1,107 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ShipmentRepository defines the interface for shipment data access.
+// The batch getters return the shipments found, in no particular order.
+// Create and UpdateStatus write the tracking event and the outbox row
+// in the same transaction as the shipment change. When ifVersion is non-nil,
+// UpdateStatus only applies if the shipment's current version is listed in it
+// and returns ErrVersionMismatch otherwise. It reports the status it moved
+// the shipment from.
 type ShipmentRepository interface {
 	GetByTrackingNumber(ctx context.Context, trackingNumber string) (*Shipment, error)
 	GetByOrderID(ctx context.Context, orderID string) (*Shipment, error)
+	GetByTrackingNumbers(ctx context.Context, trackingNumbers []string) ([]Shipment, error)
+	GetByOrderIDs(ctx context.Context, orderIDs []int) ([]Shipment, error)
+	Create(ctx context.Context, req *CreateShipmentRequest) (*Shipment, error)
+	UpdateStatus(ctx context.Context, shipmentID int, status, description string, ifVersion []int) (*StatusUpdate, error)
+}
+
+// ShipmentExceptionRepository defines the interface for delivery exception data access.
+// Create and Resolve also append the matching tracking event in the same transaction.
+type ShipmentExceptionRepository interface {
+	Create(ctx context.Context, shipmentID int, reasonCode, description string) (*ShipmentException, error)
+	ListOpen(ctx context.Context, limit int) ([]ShipmentException, error)
+	Resolve(ctx context.Context, exceptionID int, resolution, resolvedBy string) (*ShipmentException, error)
+}
+
+// TrackingEventRepository defines read access to the shipment_events history.
+// ListSince returns events with ID greater than afterID in ID order; it feeds
+// the live tracking stream in every replica.
+type TrackingEventRepository interface {
+	LatestID(ctx context.Context) (int, error)
+	ListSince(ctx context.Context, afterID, limit int) ([]TrackingEvent, error)
+}
+
+// OutboxRepository defines the interface used by the outbox relay.
+//
+// Claim leases up to limit due events for the lease duration so that concurrent
+// relays (one per replica) do not pick the same rows. An event whose lease expires
+// without MarkDelivered or MarkFailed becomes due again (at-least-once delivery).
+// DeadLetter stops retrying an event that exhausted its attempts; it is never
+// claimed again until re-queued by ops. OldestPendingAge is zero when every
+// event has been delivered or dead-lettered.
+type OutboxRepository interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, retryIn time.Duration, lastError string) error
+	DeadLetter(ctx context.Context, id int64, lastError string) error
+	OldestPendingAge(ctx context.Context) (time.Duration, error)
+}
+
+// SubscriptionRepository defines the interface for tracking subscription data
+// access: the subscription registry and the notification delivery queue.
+// Create is idempotent per (shipment, channel, target): an existing active
+// subscription is returned instead of a duplicate, with created false and
+// without its unsubscribe token.
+type SubscriptionRepository interface {
+	Create(ctx context.Context, trackingNumber, channel, target, token string) (sub *TrackingSubscription, created bool, err error)
+	Unsubscribe(ctx context.Context, token string) error
+
+	// EnqueueNotifications queues an event for every active subscription of its
+	// shipment; re-enqueueing the same event is a no-op so relay retries do not
+	// duplicate. Deliveries of a subscription cancelled meanwhile are not claimed.
+	EnqueueNotifications(ctx context.Context, event OutboxEvent) error
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]NotificationDelivery, error)
+	MarkNotificationDelivered(ctx context.Context, id int64) error
+	MarkNotificationFailed(ctx context.Context, id int64, retryIn time.Duration, lastError string) error
+	DeadLetterNotification(ctx context.Context, id int64, lastError string) error
+}
+
+// WebhookRepository defines the interface for merchant webhook data access:
+// the subscription registry, the delivery queue and the dead-letter table.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *WebhookSubscription) (*WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	DeactivateSubscription(ctx context.Context, id int) error
+
+	// EnqueueDeliveries queues an event for each subscription; re-enqueueing the
+	// same (subscription, event) pair is a no-op so relay retries do not duplicate.
+	EnqueueDeliveries(ctx context.Context, event OutboxEvent, payload []byte, subscriptionIDs []int) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	MarkDeliveryDelivered(ctx context.Context, id int64) error
+	MarkDeliveryFailed(ctx context.Context, id int64, retryIn time.Duration, lastError string) error
+	DeadLetterDelivery(ctx context.Context, id int64, lastError string) error
+
+	ListDeadLetters(ctx context.Context, limit int) ([]WebhookDeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id int64) error
+}
+
+// IdempotencyRepository stores Idempotency-Key records.
+//
+// Claim marks the key in flight for lock and returns nil when the caller now
+// owns it: the key was unused, expired, or held by a request whose lock ran out.
+// Otherwise it returns the existing record unchanged. The owner then either
+// stores the response with Complete or gives the key up with Release. owner is
+// a token unique to the attempt; Complete and Release do nothing once another
+// attempt has taken the key over.
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, scope, key, owner, fingerprint string, lock, ttl time.Duration) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, scope, key, owner string, record *IdempotencyRecord) error
+	Release(ctx context.Context, scope, key, owner string) error
+	DeleteExpired(ctx context.Context) (int64, error)
 }
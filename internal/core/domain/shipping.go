@@ -1,28 +1,115 @@
-package domain
-
-type Shipment struct {
-	ID                int     `json:"id"`
-	OrderID           int     `json:"order_id"`
-	TrackingNumber    string  `json:"tracking_number"`
-	Carrier           string  `json:"carrier,omitempty"`
-	Status            string  `json:"status"`
-	EstimatedDelivery *string `json:"estimated_delivery,omitempty"`
-	CreatedAt         string  `json:"created_at,omitempty"`
-	UpdatedAt         string  `json:"updated_at,omitempty"`
-}
-
-type EstimateRequest struct {
-	Origin      string  `json:"origin" binding:"required"`
-	Destination string  `json:"destination" binding:"required"`
-	Weight      float64 `json:"weight" binding:"required"`
-}
-
-type EstimateResponse struct {
-	Origin           string  `json:"origin"`
-	Destination      string  `json:"destination"`
-	Weight           float64 `json:"weight"`
-	EstimatedCost    float64 `json:"estimated_cost"`
-	EstimatedDays    int     `json:"estimated_days"`
-	Currency         string  `json:"currency"`
-	Carrier          string  `json:"carrier"`
-}
+package domain
+
+type Shipment struct {
+	ID                    int                `json:"id"`
+	OrderID               int                `json:"order_id"`
+	TrackingNumber        string             `json:"tracking_number"`
+	Carrier               string             `json:"carrier,omitempty"`
+	Status                string             `json:"status"`
+	EstimatedDelivery     *string            `json:"estimated_delivery,omitempty"`
+	DestinationPostalCode string             `json:"destination_postal_code,omitempty"` // Proves ownership on public tracking
+	Exception             *ShipmentException `json:"exception,omitempty"`               // Open delivery exception, if any
+	CreatedAt             string             `json:"created_at,omitempty"`
+	UpdatedAt             string             `json:"updated_at,omitempty"`
+	Version               int                `json:"version"` // Bumped on every change; sent as the ETag
+}
+
+// ShipmentStatus is the public tracking view for callers that have not proven
+// ownership of the shipment (see logicv1.OwnershipVerifier).
+type ShipmentStatus struct {
+	TrackingNumber string `json:"tracking_number"`
+	Status         string `json:"status"`
+}
+
+// StatusEvent is the public view of a tracking event for the same callers.
+type StatusEvent struct {
+	TrackingNumber string `json:"tracking_number"`
+	Status         string `json:"status"`
+	OccurredAt     string `json:"occurred_at"`
+}
+
+// CustomerToken lets a customer see full tracking details for an order.
+type CustomerToken struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type EstimateRequest struct {
+	Origin      string  `json:"origin" binding:"required"`
+	Destination string  `json:"destination" binding:"required"`
+	Weight      float64 `json:"weight" binding:"required"`
+}
+
+type EstimateResponse struct {
+	Origin        string  `json:"origin"`
+	Destination   string  `json:"destination"`
+	Weight        float64 `json:"weight"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	EstimatedDays int     `json:"estimated_days"`
+	Currency      string  `json:"currency"`
+	Carrier       string  `json:"carrier"`
+}
+
+// BatchShipmentsResponse is the result of a batch lookup. NotFound lists the
+// requested keys (tracking numbers or order IDs) that matched no shipment.
+type BatchShipmentsResponse struct {
+	Shipments []Shipment `json:"shipments"`
+	NotFound  []string   `json:"not_found"`
+}
+
+// Shipment statuses.
+const (
+	StatusPending        = "pending"
+	StatusInTransit      = "in_transit"
+	StatusOutForDelivery = "out_for_delivery"
+	StatusDelivered      = "delivered"
+	StatusReturned       = "returned"
+	StatusCancelled      = "cancelled"
+)
+
+// statusTransitions lists the statuses reachable from each status.
+// Delivered, returned and cancelled are terminal.
+var statusTransitions = map[string][]string{
+	StatusPending:        {StatusInTransit, StatusCancelled},
+	StatusInTransit:      {StatusOutForDelivery, StatusDelivered, StatusReturned},
+	StatusOutForDelivery: {StatusDelivered, StatusInTransit, StatusReturned},
+}
+
+// IsValidStatus reports whether status is a known shipment status.
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusInTransit, StatusOutForDelivery, StatusDelivered, StatusReturned, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a shipment may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateShipmentRequest struct {
+	OrderID               int     `json:"order_id" binding:"required"`
+	TrackingNumber        string  `json:"tracking_number" binding:"required"`
+	Carrier               string  `json:"carrier"`
+	EstimatedDelivery     *string `json:"estimated_delivery"` // RFC3339
+	DestinationPostalCode string  `json:"destination_postal_code"`
+}
+
+// StatusUpdate is the result of ShipmentRepository.UpdateStatus.
+type StatusUpdate struct {
+	Shipment       *Shipment
+	PreviousStatus string
+	PreviousSince  string // RFC3339; when the shipment entered PreviousStatus
+}
+
+type UpdateStatusRequest struct {
+	Status      string `json:"status" binding:"required"`
+	Description string `json:"description"`
+}
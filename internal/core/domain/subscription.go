@@ -0,0 +1,41 @@
+package domain
+
+import "encoding/json"
+
+// Notification channels for tracking subscriptions.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// TrackingSubscription is a customer's subscription to tracking updates.
+// The UnsubscribeToken is included in every notification; it is only returned
+// to the caller that created the subscription.
+type TrackingSubscription struct {
+	ID               int    `json:"id"`
+	ShipmentID       int    `json:"shipment_id"`
+	TrackingNumber   string `json:"tracking_number"`
+	Channel          string `json:"channel"`
+	Target           string `json:"target"`
+	UnsubscribeToken string `json:"unsubscribe_token,omitempty"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// NotificationDelivery is a queued notification of one event to one subscription.
+// Payload is the ShipmentEventPayload of the event.
+type NotificationDelivery struct {
+	ID           int64
+	Subscription TrackingSubscription
+	EventID      int64
+	Payload      json.RawMessage
+	Attempts     int
+}
+
+// SubscribeRequest requires exactly one of Email or WebhookURL. PostalCode
+// (or a customer token header) proves the caller owns the shipment.
+type SubscribeRequest struct {
+	TrackingNumber string `json:"tracking_number" binding:"required"`
+	Email          string `json:"email"`
+	WebhookURL     string `json:"webhook_url"`
+	PostalCode     string `json:"postal_code"`
+}
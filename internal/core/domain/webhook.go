@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription is a merchant's registration for pushed shipment events.
+// Secret is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         int      `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`        // Empty means all event types
+	Carrier    string   `json:"carrier,omitempty"`  // Optional carrier scope
+	OrderID    *int     `json:"order_id,omitempty"` // Optional order scope
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	Secret     string   `json:"secret"` // Generated when empty
+	EventTypes []string `json:"event_types"`
+	Carrier    string   `json:"carrier"`
+	OrderID    *int     `json:"order_id"`
+}
+
+// WebhookDelivery is a queued delivery of one event to one subscription.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int
+	URL            string
+	Secret         string
+	EventID        int64
+	EventType      string
+	Payload        json.RawMessage
+	Attempts       int
+	CreatedAt      time.Time
+}
+
+// WebhookDeadLetter records a delivery that exhausted its retries.
+type WebhookDeadLetter struct {
+	ID             int64   `json:"id"`
+	DeliveryID     int64   `json:"delivery_id"`
+	SubscriptionID int     `json:"subscription_id"`
+	EventID        int64   `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Attempts       int     `json:"attempts"`
+	LastError      string  `json:"last_error,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	ReplayedAt     *string `json:"replayed_at,omitempty"`
+}
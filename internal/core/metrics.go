@@ -0,0 +1,24 @@
+package database
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	replicaUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_read_replica_up",
+		Help: "Whether the last health check of a read replica succeeded (1) or failed (0).",
+	}, []string{"host"})
+
+	reads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_reads_total",
+		Help: "Read-only queries routed by target pool; reason says why a read went to the primary.",
+	}, []string{"target", "reason"})
+
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of Postgres queries by the repository method that ran them.",
+		Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"method", "status"})
+)
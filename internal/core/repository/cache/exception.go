@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// ExceptionRepository drops the cached shipment when an exception is opened
+// or resolved, since both change the shipment's version and exception state.
+// ListOpen passes through.
+type ExceptionRepository struct {
+	domain.ShipmentExceptionRepository
+
+	shipments *ShipmentRepository
+}
+
+// NewExceptionRepository wraps next, invalidating entries of shipments.
+func NewExceptionRepository(next domain.ShipmentExceptionRepository, shipments *ShipmentRepository) *ExceptionRepository {
+	return &ExceptionRepository{ShipmentExceptionRepository: next, shipments: shipments}
+}
+
+func (r *ExceptionRepository) Create(
+	ctx context.Context, shipmentID int, reasonCode, description string,
+) (*domain.ShipmentException, error) {
+	exception, err := r.ShipmentExceptionRepository.Create(ctx, shipmentID, reasonCode, description)
+	if err != nil {
+		return nil, err
+	}
+	r.shipments.Invalidate(ctx, exception.TrackingNumber)
+	return exception, nil
+}
+
+func (r *ExceptionRepository) Resolve(
+	ctx context.Context, exceptionID int, resolution, resolvedBy string,
+) (*domain.ShipmentException, error) {
+	exception, err := r.ShipmentExceptionRepository.Resolve(ctx, exceptionID, resolution, resolvedBy)
+	if err != nil {
+		return nil, err
+	}
+	r.shipments.Invalidate(ctx, exception.TrackingNumber)
+	return exception, nil
+}
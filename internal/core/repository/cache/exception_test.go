@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// exceptionDB opens and resolves exceptions on the shipments of a countingRepo,
+// bumping their version like the Postgres repository.
+type exceptionDB struct {
+	domain.ShipmentExceptionRepository
+	shipments *countingRepo
+}
+
+func (r *exceptionDB) update(shipmentID int, exception *domain.ShipmentException) string {
+	r.shipments.mu.Lock()
+	defer r.shipments.mu.Unlock()
+	for trackingNumber, s := range r.shipments.shipments {
+		if s.ID == shipmentID {
+			s.Exception = exception
+			s.Version++
+			r.shipments.shipments[trackingNumber] = s
+			return trackingNumber
+		}
+	}
+	return ""
+}
+
+func (r *exceptionDB) Create(
+	_ context.Context, shipmentID int, reasonCode, _ string,
+) (*domain.ShipmentException, error) {
+	exception := &domain.ShipmentException{ID: 1, ShipmentID: shipmentID, ReasonCode: reasonCode}
+	exception.TrackingNumber = r.update(shipmentID, exception)
+	return exception, nil
+}
+
+func (r *exceptionDB) Resolve(
+	_ context.Context, exceptionID int, resolution, _ string,
+) (*domain.ShipmentException, error) {
+	exception := &domain.ShipmentException{ID: exceptionID, ShipmentID: 1, Resolution: resolution}
+	exception.TrackingNumber = r.update(1, nil)
+	return exception, nil
+}
+
+func TestExceptionWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	shared := &mapSharedCache{items: map[string][]byte{}}
+	r, db, _ := newTestCache(shared)
+	exceptions := NewExceptionRepository(&exceptionDB{shipments: db}, r)
+
+	if s, err := r.GetByTrackingNumber(ctx, "TRK1"); err != nil || s.Exception != nil {
+		t.Fatalf("before raise = %+v, %v", s, err)
+	}
+	if _, err := exceptions.Create(ctx, 1, domain.ExceptionDamaged, ""); err != nil {
+		t.Fatal(err)
+	}
+	s, err := r.GetByTrackingNumber(ctx, "TRK1")
+	if err != nil || s.Exception == nil || s.Exception.ReasonCode != domain.ExceptionDamaged || s.Version != 2 {
+		t.Fatalf("after raise = %+v, %v, want the open exception at version 2", s, err)
+	}
+
+	if _, err := exceptions.Resolve(ctx, 1, "redelivered", "ops"); err != nil {
+		t.Fatal(err)
+	}
+	s, err = r.GetByTrackingNumber(ctx, "TRK1")
+	if err != nil || s.Exception != nil || s.Version != 3 {
+		t.Fatalf("after resolve = %+v, %v, want no exception at version 3", s, err)
+	}
+	if got := db.calls.Load(); got != 3 {
+		t.Errorf("database loads = %d, want 3", got)
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// lru is a size-bounded, least-recently-used map with a TTL per entry. A nil
+// shipment is a cached not-found.
+//
+// epoch advances on every remove. A loader reads it before going to the
+// database and passes it to add, which then refuses to store a result that
+// an invalidation may have made stale in the meantime.
+type lru struct {
+	size int
+	now  func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // Front is the most recently used
+	items map[string]*list.Element
+	epoch uint64
+}
+
+type lruEntry struct {
+	key      string
+	shipment *domain.Shipment
+	expires  time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached shipment (nil for a cached not-found) and whether a
+// live entry exists. Expired entries are dropped on access.
+func (c *lru) get(key string) (*domain.Shipment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.shipment, true
+}
+
+func (c *lru) currentEpoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch
+}
+
+// add stores an entry unless an invalidation happened since epoch, and
+// reports whether it did.
+func (c *lru) add(key string, shipment *domain.Shipment, ttl time.Duration, epoch uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch != epoch {
+		return false
+	}
+
+	expires := c.now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.shipment, entry.expires = shipment, expires
+		c.order.MoveToFront(el)
+		return true
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, shipment: shipment, expires: expires})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+	return true
+}
+
+func (c *lru) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// tier is "local" (in-process LRU) or "shared"; result is "hit",
+	// "negative_hit" (cached not-found) or "miss".
+	lookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipment_cache_lookups_total",
+			Help: "Tracking number lookups served by the shipment cache, by tier and result",
+		},
+		[]string{"tier", "result"},
+	)
+
+	// Misses deduplicated by singleflight share one load, so loads can be far
+	// fewer than local misses during a spike.
+	loads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipment_cache_loads_total",
+			Help: "Shipment cache fills, by source (shared cache or database)",
+		},
+		[]string{"source"},
+	)
+
+	invalidations = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "shipment_cache_invalidations_total",
+			Help: "Tracking numbers dropped from the shipment cache after a change",
+		},
+	)
+)
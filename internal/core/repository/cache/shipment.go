@@ -0,0 +1,250 @@
+// Package cache provides read-through caching decorators for the repositories.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedKeyPrefix namespaces shipment entries in a SharedCache.
+const sharedKeyPrefix = "shipping:shipment:tracking:"
+
+// loadTimeout bounds a shared load, which no single caller can cancel.
+const loadTimeout = 10 * time.Second
+
+// SharedCache is a cache shared by all replicas, such as Redis or memcached.
+// Get reports found=false for a missing key; errors are logged and treated
+// as misses, so an outage of the shared cache only costs database reads.
+type SharedCache interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config tunes ShipmentRepository.
+type Config struct {
+	Size        int           // Max tracking numbers kept in process
+	TTL         time.Duration // How long a found shipment is served from cache
+	NegativeTTL time.Duration // How long a not-found tracking number is remembered
+}
+
+// ShipmentRepository caches GetByTrackingNumber, the lookup behind public
+// tracking, in front of another domain.ShipmentRepository. Other reads pass
+// through.
+//
+// Lookups try the in-process LRU, then the optional SharedCache, then the
+// database. Concurrent misses for one tracking number share a single load, and
+// not-found results are cached for NegativeTTL so unknown numbers cannot
+// hammer Postgres.
+//
+// Create and UpdateStatus, and exception writes through ExceptionRepository,
+// invalidate the tracking number in this replica and in the shared cache. Other replicas learn of changes through Invalidate
+// (fed from the shipment_events tail) or when the TTL runs out, which bounds
+// staleness.
+type ShipmentRepository struct {
+	domain.ShipmentRepository // Uncached methods
+
+	next   domain.ShipmentRepository
+	cfg    Config
+	local  *lru
+	shared SharedCache
+	group  singleflight.Group
+	logger *zap.Logger
+}
+
+// NewShipmentRepository wraps next. shared may be nil for an in-process cache only.
+func NewShipmentRepository(
+	next domain.ShipmentRepository, shared SharedCache, cfg Config, logger *zap.Logger,
+) *ShipmentRepository {
+	return &ShipmentRepository{
+		ShipmentRepository: next,
+		next:               next,
+		cfg:                cfg,
+		local:              newLRU(cfg.Size),
+		shared:             shared,
+		logger:             logger,
+	}
+}
+
+// sharedEntry is the SharedCache encoding; a nil Shipment is a cached not-found.
+type sharedEntry struct {
+	Shipment *domain.Shipment `json:"shipment"`
+}
+
+func (r *ShipmentRepository) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*domain.Shipment, error) {
+	span := trace.SpanFromContext(ctx)
+
+	if shipment, ok := r.local.get(trackingNumber); ok {
+		span.SetAttributes(attribute.String("cache.result", "local_hit"))
+		if shipment == nil {
+			lookups.WithLabelValues("local", "negative_hit").Inc()
+			return nil, notFound(trackingNumber)
+		}
+		lookups.WithLabelValues("local", "hit").Inc()
+		return copyShipment(shipment), nil
+	}
+	lookups.WithLabelValues("local", "miss").Inc()
+
+	// The load outlives a caller that gives up, since other callers may be waiting on it.
+	// It reads the primary: a lagging replica could cache a row older than the
+	// invalidation that caused the miss.
+	ch := r.group.DoChan(trackingNumber, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(database.WithPrimary(context.WithoutCancel(ctx)), loadTimeout)
+		defer cancel()
+		return r.load(loadCtx, trackingNumber)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		span.SetAttributes(attribute.String("cache.result", "miss"), attribute.Bool("cache.shared_load", res.Shared))
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return copyShipment(res.Val.(*domain.Shipment)), nil
+	}
+}
+
+// load fills the local cache from the shared cache or the database.
+func (r *ShipmentRepository) load(ctx context.Context, trackingNumber string) (*domain.Shipment, error) {
+	epoch := r.local.currentEpoch()
+
+	if shipment, ok := r.getShared(ctx, trackingNumber); ok {
+		loads.WithLabelValues("shared").Inc()
+		r.store(ctx, epoch, trackingNumber, shipment, false)
+		if shipment == nil {
+			return nil, notFound(trackingNumber)
+		}
+		return shipment, nil
+	}
+
+	loads.WithLabelValues("database").Inc()
+	shipment, err := r.next.GetByTrackingNumber(ctx, trackingNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrShipmentNotFound) {
+			r.store(ctx, epoch, trackingNumber, nil, true)
+		}
+		return nil, err
+	}
+	r.store(ctx, epoch, trackingNumber, shipment, true)
+	return shipment, nil
+}
+
+// getShared returns the shared cache entry and whether there was one.
+func (r *ShipmentRepository) getShared(ctx context.Context, trackingNumber string) (*domain.Shipment, bool) {
+	if r.shared == nil {
+		return nil, false
+	}
+
+	value, found, err := r.shared.Get(ctx, sharedKeyPrefix+trackingNumber)
+	if err != nil {
+		r.logger.Warn("Shared shipment cache read failed", zap.Error(err))
+	}
+	if err != nil || !found {
+		lookups.WithLabelValues("shared", "miss").Inc()
+		return nil, false
+	}
+
+	var entry sharedEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		r.logger.Warn("Discarding undecodable shared shipment cache entry", zap.Error(err))
+		lookups.WithLabelValues("shared", "miss").Inc()
+		return nil, false
+	}
+	if entry.Shipment == nil {
+		lookups.WithLabelValues("shared", "negative_hit").Inc()
+	} else {
+		lookups.WithLabelValues("shared", "hit").Inc()
+	}
+	return entry.Shipment, true
+}
+
+// store caches a load result unless an invalidation happened since epoch.
+func (r *ShipmentRepository) store(
+	ctx context.Context, epoch uint64, trackingNumber string, shipment *domain.Shipment, toShared bool,
+) {
+	ttl := r.cfg.TTL
+	if shipment == nil {
+		ttl = r.cfg.NegativeTTL
+	}
+	if !r.local.add(trackingNumber, shipment, ttl, epoch) {
+		return
+	}
+
+	if !toShared || r.shared == nil {
+		return
+	}
+	value, err := json.Marshal(sharedEntry{Shipment: shipment})
+	if err != nil {
+		r.logger.Warn("Failed to encode shipment for the shared cache", zap.Error(err))
+		return
+	}
+	if err := r.shared.Set(ctx, sharedKeyPrefix+trackingNumber, value, ttl); err != nil {
+		r.logger.Warn("Shared shipment cache write failed", zap.Error(err))
+	}
+}
+
+// Create invalidates a cached not-found for the new tracking number.
+func (r *ShipmentRepository) Create(ctx context.Context, req *domain.CreateShipmentRequest) (*domain.Shipment, error) {
+	shipment, err := r.next.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, shipment.TrackingNumber)
+	return shipment, nil
+}
+
+func (r *ShipmentRepository) UpdateStatus(
+	ctx context.Context, shipmentID int, status, description string, ifVersion []int,
+) (*domain.StatusUpdate, error) {
+	update, err := r.next.UpdateStatus(ctx, shipmentID, status, description, ifVersion)
+	if err != nil {
+		return nil, err
+	}
+	r.Invalidate(ctx, update.Shipment.TrackingNumber)
+	return update, nil
+}
+
+// Invalidate drops a tracking number from the local and shared caches.
+// Loads already in flight for it are not stored.
+func (r *ShipmentRepository) Invalidate(ctx context.Context, trackingNumber string) {
+	r.group.Forget(trackingNumber)
+	r.local.remove(trackingNumber)
+	invalidations.Inc()
+
+	if r.shared == nil {
+		return
+	}
+	if err := r.shared.Delete(ctx, sharedKeyPrefix+trackingNumber); err != nil {
+		r.logger.Warn("Shared shipment cache delete failed",
+			zap.String("tracking_number", trackingNumber), zap.Error(err))
+	}
+}
+
+func notFound(trackingNumber string) error {
+	return fmt.Errorf("track shipment with number %q (cached): %w", trackingNumber, domain.ErrShipmentNotFound)
+}
+
+// copyShipment returns a copy callers may modify without touching the cache.
+func copyShipment(s *domain.Shipment) *domain.Shipment {
+	c := *s
+	if s.EstimatedDelivery != nil {
+		delivery := *s.EstimatedDelivery
+		c.EstimatedDelivery = &delivery
+	}
+	if s.Exception != nil {
+		exception := *s.Exception
+		c.Exception = &exception
+	}
+	return &c
+}
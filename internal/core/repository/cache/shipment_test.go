@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// countingRepo is the database behind the cache. gate, when set, blocks loads
+// until it is closed.
+type countingRepo struct {
+	domain.ShipmentRepository
+	mu        sync.Mutex
+	shipments map[string]domain.Shipment
+	calls     atomic.Int32
+	gate      chan struct{}
+}
+
+func (r *countingRepo) GetByTrackingNumber(_ context.Context, trackingNumber string) (*domain.Shipment, error) {
+	r.calls.Add(1)
+	if r.gate != nil {
+		<-r.gate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.shipments[trackingNumber]
+	if !ok {
+		return nil, domain.ErrShipmentNotFound
+	}
+	return &s, nil
+}
+
+func (r *countingRepo) Create(_ context.Context, req *domain.CreateShipmentRequest) (*domain.Shipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := domain.Shipment{TrackingNumber: req.TrackingNumber, Status: domain.StatusPending, Version: 1}
+	r.shipments[req.TrackingNumber] = s
+	return &s, nil
+}
+
+func (r *countingRepo) UpdateStatus(
+	_ context.Context, _ int, status, _ string, _ []int,
+) (*domain.StatusUpdate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.shipments["TRK1"]
+	previous := s.Status
+	s.Status = status
+	s.Version++
+	r.shipments["TRK1"] = s
+	return &domain.StatusUpdate{Shipment: &s, PreviousStatus: previous}, nil
+}
+
+type mapSharedCache struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func (m *mapSharedCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.items[key]
+	return v, ok, nil
+}
+
+func (m *mapSharedCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[key] = value
+	return nil
+}
+
+func (m *mapSharedCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+func newTestCache(shared SharedCache) (*ShipmentRepository, *countingRepo, *time.Time) {
+	db := &countingRepo{shipments: map[string]domain.Shipment{
+		"TRK1": {ID: 1, TrackingNumber: "TRK1", Status: domain.StatusInTransit, Version: 1},
+		"TRK2": {ID: 2, TrackingNumber: "TRK2", Status: domain.StatusPending, Version: 1},
+	}}
+	r := NewShipmentRepository(db, shared, Config{Size: 2, TTL: time.Minute, NegativeTTL: time.Second}, zap.NewNop())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.local.now = func() time.Time { return now }
+	return r, db, &now
+}
+
+func TestShipmentCache(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		run       func(t *testing.T, r *ShipmentRepository, now *time.Time)
+		wantCalls int32
+	}{
+		{
+			name: "hit after first load",
+			run: func(t *testing.T, r *ShipmentRepository, _ *time.Time) {
+				for range 3 {
+					if s, err := r.GetByTrackingNumber(ctx, "TRK1"); err != nil || s.Status != domain.StatusInTransit {
+						t.Fatalf("GetByTrackingNumber() = %+v, %v", s, err)
+					}
+				}
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "not found is cached until NegativeTTL",
+			run: func(t *testing.T, r *ShipmentRepository, now *time.Time) {
+				for range 2 {
+					if _, err := r.GetByTrackingNumber(ctx, "NOPE"); !errors.Is(err, domain.ErrShipmentNotFound) {
+						t.Fatalf("err = %v, want ErrShipmentNotFound", err)
+					}
+				}
+				*now = now.Add(time.Second)
+				_, _ = r.GetByTrackingNumber(ctx, "NOPE")
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "expired entries reload",
+			run: func(t *testing.T, r *ShipmentRepository, now *time.Time) {
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+				*now = now.Add(time.Minute)
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "least recently used is evicted",
+			run: func(t *testing.T, r *ShipmentRepository, _ *time.Time) {
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+				_, _ = r.GetByTrackingNumber(ctx, "TRK2")
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+				_, _ = r.GetByTrackingNumber(ctx, "NOPE") // Evicts TRK2
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+				_, _ = r.GetByTrackingNumber(ctx, "TRK2")
+			},
+			wantCalls: 4,
+		},
+		{
+			name: "status update invalidates",
+			run: func(t *testing.T, r *ShipmentRepository, _ *time.Time) {
+				_, _ = r.GetByTrackingNumber(ctx, "TRK1")
+				if _, err := r.UpdateStatus(ctx, 1, domain.StatusDelivered, "", nil); err != nil {
+					t.Fatal(err)
+				}
+				s, _ := r.GetByTrackingNumber(ctx, "TRK1")
+				if s.Status != domain.StatusDelivered || s.Version != 2 {
+					t.Errorf("after update = %+v, want delivered at version 2", s)
+				}
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "create clears a cached not-found",
+			run: func(t *testing.T, r *ShipmentRepository, _ *time.Time) {
+				_, _ = r.GetByTrackingNumber(ctx, "NEW")
+				if _, err := r.Create(ctx, &domain.CreateShipmentRequest{TrackingNumber: "NEW"}); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := r.GetByTrackingNumber(ctx, "NEW"); err != nil {
+					t.Errorf("after create: %v", err)
+				}
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "callers cannot modify cached entries",
+			run: func(t *testing.T, r *ShipmentRepository, _ *time.Time) {
+				s, _ := r.GetByTrackingNumber(ctx, "TRK1")
+				s.Status = "tampered"
+				if s, _ := r.GetByTrackingNumber(ctx, "TRK1"); s.Status != domain.StatusInTransit {
+					t.Errorf("cached status = %q", s.Status)
+				}
+			},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, db, now := newTestCache(nil)
+			tt.run(t, r, now)
+			if got := db.calls.Load(); got != tt.wantCalls {
+				t.Errorf("database loads = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestShipmentCacheDeduplicatesMisses(t *testing.T) {
+	r, db, _ := newTestCache(nil)
+	db.gate = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			if _, err := r.GetByTrackingNumber(context.Background(), "TRK1"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	for db.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Callers arriving after the load finished hit the cache instead.
+	time.Sleep(20 * time.Millisecond)
+	close(db.gate)
+	wg.Wait()
+
+	if got := db.calls.Load(); got != 1 {
+		t.Errorf("database loads = %d, want 1", got)
+	}
+}
+
+func TestShipmentCacheDropsLoadRacingInvalidation(t *testing.T) {
+	r, db, _ := newTestCache(nil)
+	db.gate = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = r.GetByTrackingNumber(context.Background(), "TRK1")
+	}()
+	for db.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	r.Invalidate(context.Background(), "TRK1") // The load in flight may have read the old row
+	close(db.gate)
+	<-done
+
+	_, _ = r.GetByTrackingNumber(context.Background(), "TRK1")
+	if got := db.calls.Load(); got != 2 {
+		t.Errorf("database loads = %d, want 2 (stale load must not be cached)", got)
+	}
+}
+
+func TestShipmentCacheSharedTier(t *testing.T) {
+	shared := &mapSharedCache{items: make(map[string][]byte)}
+	replicaA, dbA, _ := newTestCache(shared)
+	replicaB, dbB, _ := newTestCache(shared)
+	ctx := context.Background()
+
+	_, _ = replicaA.GetByTrackingNumber(ctx, "TRK1")
+	_, _ = replicaA.GetByTrackingNumber(ctx, "NOPE")
+
+	s, err := replicaB.GetByTrackingNumber(ctx, "TRK1")
+	if err != nil || s.TrackingNumber != "TRK1" {
+		t.Fatalf("replica B = %+v, %v", s, err)
+	}
+	if _, err := replicaB.GetByTrackingNumber(ctx, "NOPE"); !errors.Is(err, domain.ErrShipmentNotFound) {
+		t.Errorf("replica B not-found err = %v", err)
+	}
+	if dbA.calls.Load() != 2 || dbB.calls.Load() != 0 {
+		t.Errorf("database loads = %d and %d, want 2 and 0", dbA.calls.Load(), dbB.calls.Load())
+	}
+
+	replicaA.Invalidate(ctx, "TRK1")
+	if _, ok, _ := shared.Get(ctx, sharedKeyPrefix+"TRK1"); ok {
+		t.Error("shared entry survived invalidation")
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TrackingEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTrackingEventRepository(db *pgxpool.Pool) *TrackingEventRepository {
+	return &TrackingEventRepository{db: db}
+}
+
+func (r *TrackingEventRepository) LatestID(ctx context.Context) (int, error) {
+	var id int
+	if err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM shipment_events`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("query latest tracking event: %w", err)
+	}
+	return id, nil
+}
+
+func (r *TrackingEventRepository) ListSince(ctx context.Context, afterID, limit int) ([]domain.TrackingEvent, error) {
+	query := `
+		SELECT ev.id, ev.shipment_id, s.tracking_number, ev.event_type, ev.status,
+			COALESCE(ev.description, ''), ev.occurred_at
+		FROM shipment_events ev
+		JOIN shipments s ON s.id = ev.shipment_id
+		WHERE ev.id > $1
+		ORDER BY ev.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query tracking events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.TrackingEvent, 0)
+	for rows.Next() {
+		var event domain.TrackingEvent
+		var occurredAt time.Time
+		if err := rows.Scan(
+			&event.ID, &event.ShipmentID, &event.TrackingNumber, &event.EventType, &event.Status,
+			&event.Description, &occurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan tracking event: %w", err)
+		}
+		event.OccurredAt = occurredAt.Format(time.RFC3339)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tracking events: %w", err)
+	}
+
+	return events, nil
+}
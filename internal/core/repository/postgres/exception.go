@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgreSQL error codes used to translate constraint violations into domain errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+const exceptionColumns = `id, shipment_id, reason_code, description, created_at, resolved_at, resolution, resolved_by`
+
+// ExceptionRepository writes to the primary and lists open exceptions from a
+// read replica when the cluster has a healthy one.
+type ExceptionRepository struct {
+	db      *pgxpool.Pool
+	cluster *database.Cluster
+}
+
+func NewExceptionRepository(cluster *database.Cluster) *ExceptionRepository {
+	return &ExceptionRepository{db: cluster.Primary(), cluster: cluster}
+}
+
+// Create opens a new exception for the shipment and records an exception_opened tracking event.
+func (r *ExceptionRepository) Create(
+	ctx context.Context, shipmentID int, reasonCode, description string,
+) (*domain.ShipmentException, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		INSERT INTO shipment_exceptions (shipment_id, reason_code, description)
+		VALUES ($1, $2, $3)
+		RETURNING ` + exceptionColumns
+
+	exception, err := scanException(tx.QueryRow(ctx, query, shipmentID, reasonCode, description))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgUniqueViolation:
+				return nil, fmt.Errorf("open exception for shipment %d: %w", shipmentID, domain.ErrOpenExceptionExists)
+			case pgForeignKeyViolation:
+				return nil, fmt.Errorf("open exception for shipment %d: %w", shipmentID, domain.ErrShipmentNotFound)
+			}
+		}
+		return nil, fmt.Errorf("insert shipment exception: %w", err)
+	}
+
+	if err := insertEvent(ctx, tx, shipmentID, domain.EventExceptionOpened, reasonCode); err != nil {
+		return nil, err
+	}
+	if exception.TrackingNumber, err = bumpVersion(ctx, tx, shipmentID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return exception, nil
+}
+
+// ListOpen returns unresolved exceptions, oldest first.
+func (r *ExceptionRepository) ListOpen(ctx context.Context, limit int) ([]domain.ShipmentException, error) {
+	query := `
+		SELECT ` + exceptionColumns + `,
+			(SELECT tracking_number FROM shipments WHERE shipments.id = shipment_exceptions.shipment_id)
+		FROM shipment_exceptions
+		WHERE resolved_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1
+	`
+
+	rows, err := r.cluster.Reader(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query open exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	exceptions := make([]domain.ShipmentException, 0)
+	for rows.Next() {
+		var trackingNumber string
+		exception, err := scanException(rows, &trackingNumber)
+		if err != nil {
+			return nil, fmt.Errorf("scan shipment exception: %w", err)
+		}
+		exception.TrackingNumber = trackingNumber
+		exceptions = append(exceptions, *exception)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate open exceptions: %w", err)
+	}
+
+	return exceptions, nil
+}
+
+// Resolve closes an open exception and records an exception_resolved tracking event.
+func (r *ExceptionRepository) Resolve(
+	ctx context.Context, exceptionID int, resolution, resolvedBy string,
+) (*domain.ShipmentException, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		UPDATE shipment_exceptions
+		SET resolved_at = CURRENT_TIMESTAMP, resolution = $2, resolved_by = $3
+		WHERE id = $1 AND resolved_at IS NULL
+		RETURNING ` + exceptionColumns
+
+	exception, err := scanException(tx.QueryRow(ctx, query, exceptionID, resolution, resolvedBy))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, r.resolveMissError(ctx, tx, exceptionID)
+		}
+		return nil, fmt.Errorf("update shipment exception: %w", err)
+	}
+
+	if err := insertEvent(ctx, tx, exception.ShipmentID, domain.EventExceptionResolved, resolution); err != nil {
+		return nil, err
+	}
+	if exception.TrackingNumber, err = bumpVersion(ctx, tx, exception.ShipmentID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return exception, nil
+}
+
+// resolveMissError distinguishes an unknown exception from one that is already resolved.
+func (r *ExceptionRepository) resolveMissError(ctx context.Context, tx pgx.Tx, exceptionID int) error {
+	var exists bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM shipment_exceptions WHERE id = $1)`, exceptionID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("query shipment exception: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("resolve exception %d: %w", exceptionID, domain.ErrExceptionNotFound)
+	}
+	return fmt.Errorf("resolve exception %d: %w", exceptionID, domain.ErrExceptionAlreadyResolved)
+}
+
+// insertEvent appends a tracking event carrying the shipment's current status.
+func insertEvent(ctx context.Context, tx pgx.Tx, shipmentID int, eventType, description string) error {
+	query := `
+		INSERT INTO shipment_events (shipment_id, event_type, status, description)
+		SELECT id, $2, COALESCE(status, 'pending'), $3 FROM shipments WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, query, shipmentID, eventType, description); err != nil {
+		return fmt.Errorf("insert tracking event: %w", err)
+	}
+	return nil
+}
+
+// scanException scans exceptionColumns, then any extra columns into extra.
+func scanException(row pgx.Row, extra ...any) (*domain.ShipmentException, error) {
+	var exception domain.ShipmentException
+	var description, resolution, resolvedBy *string
+	var createdAt time.Time
+	var resolvedAt *time.Time
+
+	dest := []any{
+		&exception.ID, &exception.ShipmentID, &exception.ReasonCode, &description,
+		&createdAt, &resolvedAt, &resolution, &resolvedBy,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	exception.Description = derefString(description)
+	exception.Resolution = derefString(resolution)
+	exception.ResolvedBy = derefString(resolvedBy)
+	exception.CreatedAt = createdAt.Format(time.RFC3339)
+	if resolvedAt != nil {
+		resolvedStr := resolvedAt.Format(time.RFC3339)
+		exception.ResolvedAt = &resolvedStr
+	}
+
+	return &exception, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Claim inserts the key, or takes over a row that expired or whose in-flight
+// lock ran out. The existing row is read in a second statement so that it
+// sees a row committed by a concurrent claim the upsert waited on.
+func (r *IdempotencyRepository) Claim(
+	ctx context.Context, scope, key, owner, fingerprint string, lock, ttl time.Duration,
+) (*domain.IdempotencyRecord, error) {
+	claim := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, owner, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP + make_interval(secs => $5), CURRENT_TIMESTAMP + make_interval(secs => $6))
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET owner = EXCLUDED.owner, fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL, response_headers = NULL, response_body = NULL,
+			locked_until = EXCLUDED.locked_until, created_at = CURRENT_TIMESTAMP, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= CURRENT_TIMESTAMP)
+		RETURNING true
+	`
+	var claimed bool
+	err := r.db.QueryRow(ctx, claim, scope, key, owner, fingerprint, lock.Seconds(), ttl.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	existing := `
+		SELECT fingerprint, COALESCE(status_code, 0), response_headers, response_body
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+	var record domain.IdempotencyRecord
+	var header []byte
+	if err := r.db.QueryRow(ctx, existing, scope, key).Scan(
+		&record.Fingerprint, &record.StatusCode, &header, &record.Body,
+	); err != nil {
+		return nil, fmt.Errorf("load idempotency key: %w", err)
+	}
+	if len(header) > 0 {
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return nil, fmt.Errorf("decode idempotency response headers: %w", err)
+		}
+	}
+	return &record, nil
+}
+
+// Complete stores the response if owner still holds the key.
+func (r *IdempotencyRepository) Complete(
+	ctx context.Context, scope, key, owner string, record *domain.IdempotencyRecord,
+) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("encode idempotency response headers: %w", err)
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_headers = $4, response_body = $5
+		WHERE scope = $1 AND idempotency_key = $2 AND owner = $6 AND status_code IS NULL
+	`
+	if _, err := r.db.Exec(ctx, query, scope, key, record.StatusCode, string(header), record.Body, owner); err != nil {
+		return fmt.Errorf("store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release deletes an in-flight key held by owner so the client can retry with it.
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key, owner string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND owner = $3 AND status_code IS NULL
+	`
+	if _, err := r.db.Exec(ctx, query, scope, key, owner); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxErrorLength truncates last_error columns so a verbose failure cannot bloat the row.
+const maxErrorLength = 1000
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Claim leases due events by pushing next_attempt_at past the lease.
+// FOR UPDATE SKIP LOCKED lets relays in several replicas claim disjoint batches.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEvent, error) {
+	query := `
+		UPDATE shipment_outbox
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM shipment_outbox
+			WHERE delivered_at IS NULL AND dead_lettered_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, shipment_id, event_type, payload, attempts, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.OutboxEvent, 0, limit)
+	for rows.Next() {
+		var event domain.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(
+			&event.ID, &event.ShipmentID, &event.EventType, &payload, &event.Attempts, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	query := `
+		UPDATE shipment_outbox
+		SET delivered_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("mark outbox event %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, retryIn time.Duration, lastError string) error {
+	query := `
+		UPDATE shipment_outbox
+		SET attempts = attempts + 1, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), last_error = $3
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id, retryIn.Seconds(), truncateError(lastError)); err != nil {
+		return fmt.Errorf("mark outbox event %d failed: %w", id, err)
+	}
+	return nil
+}
+
+// DeadLetter stops retrying an event. The row keeps its payload and last error
+// until ops re-queue it by clearing dead_lettered_at.
+func (r *OutboxRepository) DeadLetter(ctx context.Context, id int64, lastError string) error {
+	query := `
+		UPDATE shipment_outbox
+		SET attempts = attempts + 1, dead_lettered_at = CURRENT_TIMESTAMP, last_error = $2
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id, truncateError(lastError)); err != nil {
+		return fmt.Errorf("dead-letter outbox event %d: %w", id, err)
+	}
+	return nil
+}
+
+// OldestPendingAge returns how long the oldest undelivered event has waited.
+// Dead-lettered events are excluded: they are no longer retried.
+func (r *OutboxRepository) OldestPendingAge(ctx context.Context) (time.Duration, error) {
+	query := `
+		SELECT COALESCE(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - MIN(created_at)), 0)::float8
+		FROM shipment_outbox
+		WHERE delivered_at IS NULL AND dead_lettered_at IS NULL
+	`
+	var seconds float64
+	if err := r.db.QueryRow(ctx, query).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("query oldest pending outbox event: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func truncateError(lastError string) string {
+	if len(lastError) > maxErrorLength {
+		return lastError[:maxErrorLength]
+	}
+	return lastError
+}
@@ -2,32 +2,47 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	database "github.com/duynhne/shipping-service/internal/core"
 	"github.com/duynhne/shipping-service/internal/core/domain"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// shipmentColumns selects a shipment together with its open exception (if any).
+// The partial unique index on shipment_exceptions guarantees at most one open row.
+const shipmentColumns = `s.id, s.order_id, s.tracking_number, s.carrier, s.status, s.estimated_delivery,
+		s.destination_postal_code, s.created_at, s.updated_at, s.version, e.id, e.reason_code, e.description, e.created_at`
+
+const shipmentFrom = `
+		FROM shipments s
+		LEFT JOIN shipment_exceptions e ON e.shipment_id = s.id AND e.resolved_at IS NULL`
+
+// ShipmentRepository writes to the primary and serves lookups from a read
+// replica when the cluster has a healthy one.
 type ShipmentRepository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	cluster *database.Cluster
 }
 
-func NewShipmentRepository(db *pgxpool.Pool) *ShipmentRepository {
-	return &ShipmentRepository{db: db}
+func NewShipmentRepository(cluster *database.Cluster) *ShipmentRepository {
+	return &ShipmentRepository{db: cluster.Primary(), cluster: cluster}
 }
 
 func (r *ShipmentRepository) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*domain.Shipment, error) {
 	query := `
-		SELECT id, order_id, tracking_number, carrier, status, estimated_delivery, created_at, updated_at
-		FROM shipments
-		WHERE tracking_number = $1
+		SELECT ` + shipmentColumns + shipmentFrom + `
+		WHERE s.tracking_number = $1
 		LIMIT 1
 	`
 
-	row := r.db.QueryRow(ctx, query, trackingNumber)
+	row := r.cluster.Reader(ctx).QueryRow(ctx, query, trackingNumber)
 	shipment, err := r.scanShipment(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -41,13 +56,12 @@ func (r *ShipmentRepository) GetByTrackingNumber(ctx context.Context, trackingNu
 
 func (r *ShipmentRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Shipment, error) {
 	query := `
-		SELECT id, order_id, tracking_number, carrier, status, estimated_delivery, created_at, updated_at
-		FROM shipments
-		WHERE order_id = $1
+		SELECT ` + shipmentColumns + shipmentFrom + `
+		WHERE s.order_id = $1
 		LIMIT 1
 	`
 
-	row := r.db.QueryRow(ctx, query, orderID)
+	row := r.cluster.Reader(ctx).QueryRow(ctx, query, orderID)
 	shipment, err := r.scanShipment(row)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -59,26 +73,253 @@ func (r *ShipmentRepository) GetByOrderID(ctx context.Context, orderID string) (
 	return shipment, nil
 }
 
+func (r *ShipmentRepository) GetByTrackingNumbers(ctx context.Context, trackingNumbers []string) ([]domain.Shipment, error) {
+	query := `
+		SELECT ` + shipmentColumns + shipmentFrom + `
+		WHERE s.tracking_number = ANY($1)
+	`
+	return r.queryShipments(ctx, query, trackingNumbers)
+}
+
+// GetByOrderIDs returns one shipment per order, matching GetByOrderID.
+func (r *ShipmentRepository) GetByOrderIDs(ctx context.Context, orderIDs []int) ([]domain.Shipment, error) {
+	query := `
+		SELECT DISTINCT ON (s.order_id) ` + shipmentColumns + shipmentFrom + `
+		WHERE s.order_id = ANY($1)
+		ORDER BY s.order_id, s.id
+	`
+	return r.queryShipments(ctx, query, orderIDs)
+}
+
+func (r *ShipmentRepository) queryShipments(ctx context.Context, query string, args ...any) ([]domain.Shipment, error) {
+	rows, err := r.cluster.Reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query shipments: %w", err)
+	}
+	defer rows.Close()
+
+	shipments := make([]domain.Shipment, 0)
+	for rows.Next() {
+		shipment, err := r.scanShipment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan shipment: %w", err)
+		}
+		shipments = append(shipments, *shipment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate shipments: %w", err)
+	}
+
+	return shipments, nil
+}
+
+// Create inserts a pending shipment, its "created" tracking event and a
+// shipment.created outbox row in one transaction.
+func (r *ShipmentRepository) Create(ctx context.Context, req *domain.CreateShipmentRequest) (*domain.Shipment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		INSERT INTO shipments (order_id, tracking_number, carrier, status, estimated_delivery, destination_postal_code)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING id
+	`
+
+	var shipmentID int
+	err = tx.QueryRow(ctx, query,
+		req.OrderID, req.TrackingNumber, req.Carrier, domain.StatusPending, req.EstimatedDelivery, req.DestinationPostalCode,
+	).Scan(&shipmentID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, fmt.Errorf("create shipment %q: %w", req.TrackingNumber, domain.ErrDuplicateTrackingNumber)
+		}
+		return nil, fmt.Errorf("insert shipment: %w", err)
+	}
+
+	shipment, err := r.getByID(ctx, tx, shipmentID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertEvent(ctx, tx, shipmentID, domain.EventCreated, ""); err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, domain.OutboxShipmentCreated, shipment, "", ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return shipment, nil
+}
+
+// UpdateStatus moves a shipment to a new status under a row lock, recording the
+// tracking event and a shipment.status_changed outbox row in the same transaction.
+// Transitions not allowed by domain.CanTransition return domain.ErrInvalidStatusTransition;
+// the row lock makes the ifVersion check and the version bump atomic.
+func (r *ShipmentRepository) UpdateStatus(
+	ctx context.Context, shipmentID int, status, description string, ifVersion []int,
+) (*domain.StatusUpdate, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := r.getByID(ctx, tx, shipmentID, true)
+	if err != nil {
+		return nil, err
+	}
+	since, err := statusEnteredAt(ctx, tx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if ifVersion != nil && !slices.Contains(ifVersion, current.Version) {
+		return nil, fmt.Errorf("update shipment %d at version %d: %w", shipmentID, current.Version, domain.ErrVersionMismatch)
+	}
+	if !domain.CanTransition(current.Status, status) {
+		return nil, fmt.Errorf("update shipment %d from %q to %q: %w",
+			shipmentID, current.Status, status, domain.ErrInvalidStatusTransition)
+	}
+
+	query := `UPDATE shipments SET status = $2, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, shipmentID, status); err != nil {
+		return nil, fmt.Errorf("update shipment status: %w", err)
+	}
+
+	shipment, err := r.getByID(ctx, tx, shipmentID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertEvent(ctx, tx, shipmentID, domain.EventStatusChanged, description); err != nil {
+		return nil, err
+	}
+	err = insertOutbox(ctx, tx, domain.OutboxShipmentStatusChanged, shipment, current.Status, description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return &domain.StatusUpdate{Shipment: shipment, PreviousStatus: current.Status, PreviousSince: since}, nil
+}
+
+// statusEnteredAt returns when the shipment entered its current status: the
+// latest created or status_changed tracking event, or created_at for shipments
+// recorded before shipment_events existed.
+func statusEnteredAt(ctx context.Context, tx pgx.Tx, shipmentID int) (string, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT ev.occurred_at FROM shipment_events ev
+			 WHERE ev.shipment_id = s.id AND ev.event_type IN ($2, $3)
+			 ORDER BY ev.occurred_at DESC, ev.id DESC LIMIT 1),
+			s.created_at)
+		FROM shipments s
+		WHERE s.id = $1
+	`
+	var since time.Time
+	err := tx.QueryRow(ctx, query, shipmentID, domain.EventCreated, domain.EventStatusChanged).Scan(&since)
+	if err != nil {
+		return "", fmt.Errorf("query status entered time: %w", err)
+	}
+	return since.Format(time.RFC3339), nil
+}
+
+// getByID loads a shipment inside a transaction, optionally locking the row.
+func (r *ShipmentRepository) getByID(ctx context.Context, tx pgx.Tx, shipmentID int, forUpdate bool) (*domain.Shipment, error) {
+	query := `
+		SELECT ` + shipmentColumns + shipmentFrom + `
+		WHERE s.id = $1
+	`
+	if forUpdate {
+		query += ` FOR UPDATE OF s`
+	}
+
+	shipment, err := r.scanShipment(tx.QueryRow(ctx, query, shipmentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("get shipment %d: %w", shipmentID, domain.ErrShipmentNotFound)
+		}
+		return nil, fmt.Errorf("query shipment: %w", err)
+	}
+
+	return shipment, nil
+}
+
+// bumpVersion marks a change to the shipment's representation that is not a
+// status update, e.g. an exception opened or resolved, so cached ETags go stale.
+// It returns the tracking number, under which the shipment is cached.
+func bumpVersion(ctx context.Context, tx pgx.Tx, shipmentID int) (string, error) {
+	var trackingNumber string
+	query := `UPDATE shipments SET version = version + 1 WHERE id = $1 RETURNING tracking_number`
+	if err := tx.QueryRow(ctx, query, shipmentID).Scan(&trackingNumber); err != nil {
+		return "", fmt.Errorf("bump shipment version: %w", err)
+	}
+	return trackingNumber, nil
+}
+
+// insertOutbox writes a shipment event to shipment_outbox for the outbox relay.
+func insertOutbox(
+	ctx context.Context, tx pgx.Tx, eventType string, shipment *domain.Shipment, previousStatus, description string,
+) error {
+	payload, err := json.Marshal(domain.ShipmentEventPayload{
+		ShipmentID:     shipment.ID,
+		OrderID:        shipment.OrderID,
+		TrackingNumber: shipment.TrackingNumber,
+		Carrier:        shipment.Carrier,
+		PreviousStatus: previousStatus,
+		Status:         shipment.Status,
+		Description:    description,
+		OccurredAt:     shipment.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal outbox payload: %w", err)
+	}
+
+	query := `INSERT INTO shipment_outbox (shipment_id, event_type, payload) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, query, shipment.ID, eventType, string(payload)); err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	return nil
+}
+
 func (r *ShipmentRepository) scanShipment(row pgx.Row) (*domain.Shipment, error) {
 	var id, orderID int
 	var trackingNum, carrier, status string
 	var estimatedDelivery *time.Time
+	var destinationPostalCode *string
 	var createdAt, updatedAt time.Time
+	var version int
+	var exceptionID *int
+	var exceptionReason, exceptionDescription *string
+	var exceptionCreatedAt *time.Time
 
 	err := row.Scan(
-		&id, &orderID, &trackingNum, &carrier, &status, &estimatedDelivery, &createdAt, &updatedAt,
+		&id, &orderID, &trackingNum, &carrier, &status, &estimatedDelivery, &destinationPostalCode, &createdAt, &updatedAt, &version,
+		&exceptionID, &exceptionReason, &exceptionDescription, &exceptionCreatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	shipment := &domain.Shipment{
-		ID:             id,
-		OrderID:        orderID,
-		TrackingNumber: trackingNum,
-		Status:         status,
-		CreatedAt:      createdAt.Format(time.RFC3339),
-		UpdatedAt:      updatedAt.Format(time.RFC3339),
+		ID:                    id,
+		OrderID:               orderID,
+		TrackingNumber:        trackingNum,
+		Status:                status,
+		DestinationPostalCode: derefString(destinationPostalCode),
+		CreatedAt:             createdAt.Format(time.RFC3339),
+		UpdatedAt:             updatedAt.Format(time.RFC3339),
+		Version:               version,
 	}
 
 	if carrier != "" {
@@ -90,5 +331,24 @@ func (r *ShipmentRepository) scanShipment(row pgx.Row) (*domain.Shipment, error)
 		shipment.EstimatedDelivery = &deliveryStr
 	}
 
+	if exceptionID != nil {
+		shipment.Exception = &domain.ShipmentException{
+			ID:          *exceptionID,
+			ShipmentID:  id,
+			ReasonCode:  derefString(exceptionReason),
+			Description: derefString(exceptionDescription),
+		}
+		if exceptionCreatedAt != nil {
+			shipment.Exception.CreatedAt = exceptionCreatedAt.Format(time.RFC3339)
+		}
+	}
+
 	return shipment, nil
 }
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
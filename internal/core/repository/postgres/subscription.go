@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubscriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// Create subscribes a target to a shipment identified by tracking number.
+// Re-subscribing the same target returns the existing active subscription
+// without its unsubscribe token, which only the original subscriber receives.
+func (r *SubscriptionRepository) Create(
+	ctx context.Context, trackingNumber, channel, target, token string,
+) (*domain.TrackingSubscription, bool, error) {
+	query := `
+		WITH shipment AS (
+			SELECT id, tracking_number FROM shipments WHERE tracking_number = $1
+		), inserted AS (
+			INSERT INTO tracking_subscriptions (shipment_id, channel, target, unsubscribe_token)
+			SELECT id, $2, $3, $4 FROM shipment
+			ON CONFLICT (shipment_id, channel, target) WHERE unsubscribed_at IS NULL DO NOTHING
+			RETURNING id, shipment_id, channel, target, unsubscribe_token, created_at
+		)
+		SELECT i.id, i.shipment_id, s.tracking_number, i.channel, i.target, i.unsubscribe_token, i.created_at, true
+		FROM inserted i JOIN shipment s ON s.id = i.shipment_id
+		UNION ALL
+		SELECT t.id, t.shipment_id, s.tracking_number, t.channel, t.target, '', t.created_at, false
+		FROM tracking_subscriptions t JOIN shipment s ON s.id = t.shipment_id
+		WHERE t.channel = $2 AND t.target = $3 AND t.unsubscribed_at IS NULL
+		LIMIT 1
+	`
+
+	var subscription domain.TrackingSubscription
+	var createdAt time.Time
+	var created bool
+	err := r.db.QueryRow(ctx, query, trackingNumber, channel, target, token).Scan(
+		&subscription.ID, &subscription.ShipmentID, &subscription.TrackingNumber, &subscription.Channel,
+		&subscription.Target, &subscription.UnsubscribeToken, &createdAt, &created,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("subscribe to %q: %w", trackingNumber, domain.ErrShipmentNotFound)
+		}
+		return nil, false, fmt.Errorf("insert tracking subscription: %w", err)
+	}
+
+	subscription.CreatedAt = createdAt.Format(time.RFC3339)
+	return &subscription, created, nil
+}
+
+func (r *SubscriptionRepository) Unsubscribe(ctx context.Context, token string) error {
+	query := `
+		UPDATE tracking_subscriptions
+		SET unsubscribed_at = CURRENT_TIMESTAMP
+		WHERE unsubscribe_token = $1 AND unsubscribed_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, token)
+	if err != nil {
+		return fmt.Errorf("unsubscribe: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("unsubscribe: %w", domain.ErrSubscriptionNotFound)
+	}
+	return nil
+}
+
+// EnqueueNotifications queues a notification of the event for each active
+// subscription of its shipment.
+func (r *SubscriptionRepository) EnqueueNotifications(ctx context.Context, event domain.OutboxEvent) error {
+	query := `
+		INSERT INTO notification_deliveries (subscription_id, event_id, payload)
+		SELECT id, $2, $3 FROM tracking_subscriptions
+		WHERE shipment_id = $1 AND unsubscribed_at IS NULL
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, event.ShipmentID, event.ID, string(event.Payload)); err != nil {
+		return fmt.Errorf("enqueue notifications for event %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+// ClaimNotifications leases due notifications of active subscriptions (see OutboxRepository.Claim).
+func (r *SubscriptionRepository) ClaimNotifications(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]domain.NotificationDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE notification_deliveries
+			SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT d.id FROM notification_deliveries d
+				JOIN tracking_subscriptions t ON t.id = d.subscription_id AND t.unsubscribed_at IS NULL
+				WHERE d.delivered_at IS NULL AND d.dead_lettered_at IS NULL
+					AND d.next_attempt_at <= CURRENT_TIMESTAMP
+				ORDER BY d.id
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING id, subscription_id, event_id, payload, attempts
+		)
+		SELECT c.id, c.event_id, c.payload, c.attempts,
+			t.id, t.shipment_id, s.tracking_number, t.channel, t.target, t.unsubscribe_token, t.created_at
+		FROM claimed c
+		JOIN tracking_subscriptions t ON t.id = c.subscription_id
+		JOIN shipments s ON s.id = t.shipment_id
+		ORDER BY c.id
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.NotificationDelivery, 0, limit)
+	for rows.Next() {
+		var d domain.NotificationDelivery
+		var payload []byte
+		var createdAt time.Time
+		sub := &d.Subscription
+		if err := rows.Scan(
+			&d.ID, &d.EventID, &payload, &d.Attempts,
+			&sub.ID, &sub.ShipmentID, &sub.TrackingNumber, &sub.Channel, &sub.Target, &sub.UnsubscribeToken, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		d.Payload = payload
+		sub.CreatedAt = createdAt.Format(time.RFC3339)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notifications: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *SubscriptionRepository) MarkNotificationDelivered(ctx context.Context, id int64) error {
+	query := `
+		UPDATE notification_deliveries
+		SET delivered_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("mark notification %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) MarkNotificationFailed(
+	ctx context.Context, id int64, retryIn time.Duration, lastError string,
+) error {
+	query := `
+		UPDATE notification_deliveries
+		SET attempts = attempts + 1, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), last_error = $3
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id, retryIn.Seconds(), truncateError(lastError)); err != nil {
+		return fmt.Errorf("mark notification %d failed: %w", id, err)
+	}
+	return nil
+}
+
+// DeadLetterNotification stops retrying a notification; the row keeps its last error.
+func (r *SubscriptionRepository) DeadLetterNotification(ctx context.Context, id int64, lastError string) error {
+	query := `
+		UPDATE notification_deliveries
+		SET attempts = attempts + 1, dead_lettered_at = CURRENT_TIMESTAMP, last_error = $2
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id, truncateError(lastError)); err != nil {
+		return fmt.Errorf("dead-letter notification %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookRepository writes to the primary and lists dead letters from a read
+// replica when the cluster has a healthy one. Subscriptions are always read
+// from the primary: dispatch must see a subscription as soon as it exists.
+type WebhookRepository struct {
+	db      *pgxpool.Pool
+	cluster *database.Cluster
+}
+
+func NewWebhookRepository(cluster *database.Cluster) *WebhookRepository {
+	return &WebhookRepository{db: cluster.Primary(), cluster: cluster}
+}
+
+func (r *WebhookRepository) CreateSubscription(
+	ctx context.Context, sub *domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, carrier, order_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id, active, created_at
+	`
+
+	created := *sub
+	created.EventTypes = append([]string{}, sub.EventTypes...)
+	var createdAt time.Time
+	err := r.db.QueryRow(ctx, query, sub.URL, sub.Secret, created.EventTypes, sub.Carrier, sub.OrderID).
+		Scan(&created.ID, &created.Active, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert webhook subscription: %w", err)
+	}
+	created.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return &created, nil
+}
+
+// ListSubscriptions returns active subscriptions without their secrets.
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, event_types, COALESCE(carrier, ''), order_id, active, created_at
+		FROM webhook_subscriptions
+		WHERE active
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		var createdAt time.Time
+		if err := rows.Scan(
+			&sub.ID, &sub.URL, &sub.EventTypes, &sub.Carrier, &sub.OrderID, &sub.Active, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook subscription: %w", err)
+		}
+		sub.CreatedAt = createdAt.Format(time.RFC3339)
+		subscriptions = append(subscriptions, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WebhookRepository) DeactivateSubscription(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `UPDATE webhook_subscriptions SET active = FALSE WHERE id = $1 AND active`, id)
+	if err != nil {
+		return fmt.Errorf("deactivate webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("deactivate webhook subscription %d: %w", id, domain.ErrWebhookSubscriptionNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDeliveries(
+	ctx context.Context, event domain.OutboxEvent, payload []byte, subscriptionIDs []int,
+) error {
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT unnest($1::int[]), $2, $3, $4
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, subscriptionIDs, event.ID, event.EventType, string(payload)); err != nil {
+		return fmt.Errorf("enqueue webhook deliveries for event %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+// ClaimDeliveries leases due deliveries of active subscriptions (see OutboxRepository.Claim).
+func (r *WebhookRepository) ClaimDeliveries(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]domain.WebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id AND s.active
+				WHERE d.delivered_at IS NULL AND d.dead_lettered_at IS NULL
+					AND d.next_attempt_at <= CURRENT_TIMESTAMP
+				ORDER BY d.id
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING id, subscription_id, event_id, event_type, payload, attempts, created_at
+		)
+		SELECT c.id, c.subscription_id, s.url, s.secret, c.event_id, c.event_type, c.payload, c.attempts, c.created_at
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		ORDER BY c.id
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.URL, &d.Secret, &d.EventID, &d.EventType, &payload, &d.Attempts, &d.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		d.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDeliveryDelivered(ctx context.Context, id int64) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET delivered_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("mark webhook delivery %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) MarkDeliveryFailed(ctx context.Context, id int64, retryIn time.Duration, lastError string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), last_error = $3
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, id, retryIn.Seconds(), truncateError(lastError)); err != nil {
+		return fmt.Errorf("mark webhook delivery %d failed: %w", id, err)
+	}
+	return nil
+}
+
+// DeadLetterDelivery stops retrying a delivery and copies it to webhook_dead_letters.
+func (r *WebhookRepository) DeadLetterDelivery(ctx context.Context, id int64, lastError string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	lastError = truncateError(lastError)
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, dead_lettered_at = CURRENT_TIMESTAMP, last_error = $2
+		WHERE id = $1
+		RETURNING subscription_id, event_id, event_type, attempts
+	`
+	var subscriptionID, attempts int
+	var eventID int64
+	var eventType string
+	if err := tx.QueryRow(ctx, query, id, lastError).Scan(&subscriptionID, &eventID, &eventType, &attempts); err != nil {
+		return fmt.Errorf("dead-letter webhook delivery %d: %w", id, err)
+	}
+
+	insert := `
+		INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event_id, event_type, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(ctx, insert, id, subscriptionID, eventID, eventType, attempts, lastError); err != nil {
+		return fmt.Errorf("insert webhook dead letter: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns dead letters that have not been replayed, oldest first.
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, limit int) ([]domain.WebhookDeadLetter, error) {
+	query := `
+		SELECT id, delivery_id, subscription_id, event_id, event_type, attempts, COALESCE(last_error, ''), created_at
+		FROM webhook_dead_letters
+		WHERE replayed_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1
+	`
+
+	rows, err := r.cluster.Reader(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := make([]domain.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var dl domain.WebhookDeadLetter
+		var createdAt time.Time
+		if err := rows.Scan(
+			&dl.ID, &dl.DeliveryID, &dl.SubscriptionID, &dl.EventID, &dl.EventType, &dl.Attempts, &dl.LastError, &createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook dead letter: %w", err)
+		}
+		dl.CreatedAt = createdAt.Format(time.RFC3339)
+		deadLetters = append(deadLetters, dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// ReplayDeadLetter re-queues the dead-lettered delivery with a fresh retry budget.
+// A dead letter of an inactive subscription is left as it is and returns
+// domain.ErrWebhookSubscriptionInactive, since ClaimDeliveries would skip it.
+func (r *WebhookRepository) ReplayDeadLetter(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var active bool
+	lookup := `
+		SELECT s.active
+		FROM webhook_dead_letters dl
+		JOIN webhook_subscriptions s ON s.id = dl.subscription_id
+		WHERE dl.id = $1 AND dl.replayed_at IS NULL
+		FOR UPDATE OF dl
+	`
+	if err := tx.QueryRow(ctx, lookup, id).Scan(&active); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("replay dead letter %d: %w", id, domain.ErrDeadLetterNotFound)
+		}
+		return fmt.Errorf("query dead letter %d: %w", id, err)
+	}
+	if !active {
+		return fmt.Errorf("replay dead letter %d: %w", id, domain.ErrWebhookSubscriptionInactive)
+	}
+
+	query := `
+		UPDATE webhook_dead_letters
+		SET replayed_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING delivery_id
+	`
+	var deliveryID int64
+	if err := tx.QueryRow(ctx, query, id).Scan(&deliveryID); err != nil {
+		return fmt.Errorf("mark dead letter %d replayed: %w", id, err)
+	}
+
+	requeue := `
+		UPDATE webhook_deliveries
+		SET attempts = 0, last_error = NULL, dead_lettered_at = NULL, next_attempt_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, requeue, deliveryID); err != nil {
+		return fmt.Errorf("requeue webhook delivery %d: %w", deliveryID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
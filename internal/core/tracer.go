@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// repositoryPackage prefixes the functions of the Postgres repositories in
+// stack traces. Queries are attributed to the outermost function found there.
+const repositoryPackage = "github.com/duynhne/shipping-service/internal/core/repository/postgres."
+
+// maxQueryStackDepth bounds the stack walk done per query.
+const maxQueryStackDepth = 32
+
+// maxStatementLength truncates db.statement on spans.
+const maxStatementLength = 2000
+
+var dbTracer = otel.Tracer("github.com/duynhne/shipping-service/internal/core")
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	method string
+	start  time.Time
+	span   trace.Span
+}
+
+// queryTracer instruments every query with the repository method that ran it
+// (e.g. "ShipmentRepository.UpdateStatus"). Statements of a transaction,
+// including BEGIN and COMMIT, count towards the method that opened it, so
+// repositories do not have to name themselves.
+//
+// Each query is recorded in db_query_duration_seconds, which separates slow
+// queries from slow handlers, and gets a client span under the caller's span
+// so traces show the database time inside e.g. shipping.track. Queries with
+// no span in their context, such as background polling, get no span rather
+// than a new trace per poll.
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	qs := queryStart{method: callerMethod(), start: time.Now()}
+
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		statement := sanitizeStatement(data.SQL)
+		operation := statementOperation(statement)
+		cfg := conn.Config()
+		ctx, qs.span = dbTracer.Start(ctx, operation+" "+qs.method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationKey.String(operation),
+				semconv.DBStatementKey.String(statement),
+				semconv.DBNameKey.String(cfg.Database),
+				semconv.ServerAddressKey.String(cfg.Host),
+				semconv.ServerPortKey.Int(int(cfg.Port)),
+				attribute.String("code.function", qs.method),
+			),
+		)
+	}
+	return context.WithValue(ctx, queryStartKey{}, qs)
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qs, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if data.Err != nil {
+		status = "error"
+	}
+	queryDuration.WithLabelValues(qs.method, status).Observe(time.Since(qs.start).Seconds())
+
+	if qs.span == nil {
+		return
+	}
+	qs.span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	if data.Err != nil {
+		qs.span.RecordError(data.Err)
+		qs.span.SetStatus(otelcodes.Error, data.Err.Error())
+	}
+	qs.span.End()
+}
+
+// callerMethod returns the outermost repository method on the stack, or
+// "other" for queries issued elsewhere.
+func callerMethod() string {
+	var pcs [maxQueryStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	method := ""
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, repositoryPackage); ok {
+			method = name
+		} else if method != "" {
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	if method == "" {
+		return "other"
+	}
+	return repositoryMethodName(method)
+}
+
+// repositoryMethodName turns "(*ShipmentRepository).Create.func1" into
+// "ShipmentRepository.Create".
+func repositoryMethodName(function string) string {
+	function = strings.NewReplacer("(*", "", ")", "").Replace(function)
+	if typ, rest, ok := strings.Cut(function, "."); ok {
+		method, _, _ := strings.Cut(rest, ".")
+		return typ + "." + method
+	}
+	return function
+}
+
+// sanitizeStatement prepares SQL for a span: whitespace is collapsed and
+// string and numeric literals become "?", so values written into a statement
+// (rather than passed as $n parameters) do not leak into traces.
+func sanitizeStatement(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	space := false
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			space = b.Len() > 0
+			continue
+		case ch == '\'':
+			// Skip to the closing quote; '' is an escaped quote inside the literal.
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			ch = '?'
+		case isDigit(ch) && !continuesToken(sql, i):
+			for i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '.') {
+				i++
+			}
+			ch = '?'
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(ch)
+		if b.Len() >= maxStatementLength {
+			break
+		}
+	}
+	return b.String()
+}
+
+// continuesToken reports whether the digit at i belongs to an identifier or a
+// $n placeholder rather than starting a numeric literal.
+func continuesToken(sql string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev := sql[i-1]
+	return prev == '$' || prev == '_' || isDigit(prev) ||
+		('a' <= prev && prev <= 'z') || ('A' <= prev && prev <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// statementOperation returns the statement's leading keyword, e.g. "SELECT".
+func statementOperation(statement string) string {
+	operation, _, _ := strings.Cut(statement, " ")
+	operation = strings.ToUpper(strings.TrimRight(operation, ";"))
+	if operation == "" {
+		return "QUERY"
+	}
+	return operation
+}
@@ -0,0 +1,77 @@
+package database
+
+import "testing"
+
+func TestRepositoryMethodName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"(*ShipmentRepository).GetByTrackingNumber", "ShipmentRepository.GetByTrackingNumber"},
+		{"(*ShipmentRepository).Create.func1", "ShipmentRepository.Create"},
+		{"ShipmentRepository.Create", "ShipmentRepository.Create"},
+		{"insertEvent", "insertEvent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			if got := repositoryMethodName(tt.function); got != tt.want {
+				t.Errorf("repositoryMethodName(%q) = %q, want %q", tt.function, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallerMethodOutsideRepositories(t *testing.T) {
+	if got := callerMethod(); got != "other" {
+		t.Errorf("callerMethod() = %q, want other", got)
+	}
+}
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			"placeholders kept, whitespace collapsed",
+			"\n\t\tSELECT s.id\n\t\tFROM shipments s\n\t\tWHERE s.tracking_number = $1\n\t\tLIMIT 1\n\t",
+			"SELECT s.id FROM shipments s WHERE s.tracking_number = $1 LIMIT ?",
+		},
+		{
+			"string literals",
+			"UPDATE shipments SET status = 'it''s delivered' WHERE email = 'a@example.com'",
+			"UPDATE shipments SET status = ? WHERE email = ?",
+		},
+		{
+			"numbers inside identifiers and casts",
+			"SELECT col2, $10::float8, 3.5 FROM t1",
+			"SELECT col2, $10::float8, ? FROM t1",
+		},
+		{"unterminated literal", "SELECT 'oops", "SELECT ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeStatement(tt.sql); got != tt.want {
+				t.Errorf("sanitizeStatement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":             "SELECT",
+		"insert into t":        "INSERT",
+		"begin":                "BEGIN",
+		"WITH x AS (SELECT ?)": "WITH",
+		"":                     "QUERY",
+	}
+	for statement, want := range tests {
+		if got := statementOperation(statement); got != want {
+			t.Errorf("statementOperation(%q) = %q, want %q", statement, got, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/grpc/v1/shippingpb"
+)
+
+// Conversions from domain types to the generated messages. Field names match,
+// so gRPC and HTTP clients see the same data.
+
+func toShipment(s *domain.Shipment) *shippingpb.Shipment {
+	msg := &shippingpb.Shipment{
+		Id:                    int64(s.ID),
+		OrderId:               int64(s.OrderID),
+		TrackingNumber:        s.TrackingNumber,
+		Carrier:               s.Carrier,
+		Status:                s.Status,
+		EstimatedDelivery:     s.EstimatedDelivery,
+		DestinationPostalCode: s.DestinationPostalCode,
+		CreatedAt:             s.CreatedAt,
+		UpdatedAt:             s.UpdatedAt,
+		Version:               int64(s.Version),
+	}
+	if e := s.Exception; e != nil {
+		msg.Exception = &shippingpb.ShipmentException{
+			Id:          int64(e.ID),
+			ShipmentId:  int64(e.ShipmentID),
+			ReasonCode:  e.ReasonCode,
+			Description: e.Description,
+			CreatedAt:   e.CreatedAt,
+		}
+	}
+	return msg
+}
+
+func toShipmentStatus(s domain.ShipmentStatus) *shippingpb.ShipmentStatus {
+	return &shippingpb.ShipmentStatus{TrackingNumber: s.TrackingNumber, Status: s.Status}
+}
+
+func toShipments(shipments []domain.Shipment) []*shippingpb.Shipment {
+	msgs := make([]*shippingpb.Shipment, len(shipments))
+	for i := range shipments {
+		msgs[i] = toShipment(&shipments[i])
+	}
+	return msgs
+}
+
+func toEstimate(e *domain.EstimateResponse) *shippingpb.EstimateShippingResponse {
+	return &shippingpb.EstimateShippingResponse{
+		Origin:        e.Origin,
+		Destination:   e.Destination,
+		Weight:        e.Weight,
+		EstimatedCost: e.EstimatedCost,
+		EstimatedDays: int32(e.EstimatedDays),
+		Currency:      e.Currency,
+		Carrier:       e.Carrier,
+	}
+}
+
+func fromParcels(parcels []*shippingpb.EstimateShippingRequest) []domain.EstimateRequest {
+	reqs := make([]domain.EstimateRequest, len(parcels))
+	for i, p := range parcels {
+		reqs[i] = domain.EstimateRequest{Origin: p.GetOrigin(), Destination: p.GetDestination(), Weight: p.GetWeight()}
+	}
+	return reqs
+}
@@ -0,0 +1,173 @@
+// Package v1 exposes the shipping read API over gRPC, backed by the same
+// logicv1.ShippingService as the HTTP handlers.
+//
+// The API is defined in proto/shipping/v1/shipping.proto; the shippingpb
+// package is generated from it with "buf generate" at the repository root.
+// Go callers use shippingpb.NewShippingServiceClient.
+package v1
+
+import (
+	"context"
+	"errors"
+
+	"github.com/duynhne/shipping-service/internal/auth"
+	"github.com/duynhne/shipping-service/internal/grpc/v1/shippingpb"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the ShippingService gRPC API.
+type Server struct {
+	shippingpb.UnimplementedShippingServiceServer
+
+	service   *logicv1.ShippingService
+	ownership *logicv1.OwnershipVerifier // nil when tracking verification is disabled
+	logger    *zap.Logger
+}
+
+func NewServer(service *logicv1.ShippingService, ownership *logicv1.OwnershipVerifier, logger *zap.Logger) *Server {
+	return &Server{
+		service:   service,
+		ownership: ownership,
+		logger:    logger,
+	}
+}
+
+// Register adds the ShippingService to a gRPC server.
+func Register(s *grpc.Server, srv *Server) {
+	shippingpb.RegisterShippingServiceServer(s, srv)
+}
+
+func (s *Server) TrackShipment(
+	ctx context.Context, req *shippingpb.TrackShipmentRequest,
+) (*shippingpb.TrackShipmentResponse, error) {
+	if req.GetTrackingNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tracking_number is required")
+	}
+
+	shipment, err := s.service.TrackShipment(ctx, req.GetTrackingNumber())
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to track shipment")
+	}
+
+	full := canReadShipments(ctx)
+	if !full {
+		proof := logicv1.OwnershipProof{PostalCode: req.GetPostalCode(), Token: req.GetCustomerToken()}
+		if full, err = s.ownership.Authorize(shipment, proof); err != nil {
+			return nil, s.toStatus(err, "Failed to verify tracking ownership")
+		}
+	}
+	if !full {
+		return &shippingpb.TrackShipmentResponse{
+			View: &shippingpb.TrackShipmentResponse_Status{Status: toShipmentStatus(logicv1.StatusOnly(shipment))},
+		}, nil
+	}
+	return &shippingpb.TrackShipmentResponse{
+		View: &shippingpb.TrackShipmentResponse_Shipment{Shipment: toShipment(shipment)},
+	}, nil
+}
+
+func (s *Server) GetShipmentByOrder(
+	ctx context.Context, req *shippingpb.GetShipmentByOrderRequest,
+) (*shippingpb.GetShipmentByOrderResponse, error) {
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	shipment, err := s.service.GetShipmentByOrderID(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to get shipment by order")
+	}
+	return &shippingpb.GetShipmentByOrderResponse{Shipment: toShipment(shipment)}, nil
+}
+
+func (s *Server) EstimateShipping(
+	ctx context.Context, req *shippingpb.EstimateShippingRequest,
+) (*shippingpb.EstimateShippingResponse, error) {
+	if req.GetOrigin() == "" || req.GetDestination() == "" || req.GetWeight() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "origin, destination and a positive weight are required")
+	}
+
+	estimate, err := s.service.EstimateShipping(ctx, req.GetOrigin(), req.GetDestination(), req.GetWeight())
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to estimate shipping")
+	}
+	return toEstimate(estimate), nil
+}
+
+func (s *Server) BatchTrackShipments(
+	ctx context.Context, req *shippingpb.BatchTrackShipmentsRequest,
+) (*shippingpb.BatchTrackShipmentsResponse, error) {
+	resp, err := s.service.TrackShipments(ctx, req.GetTrackingNumbers())
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to batch track shipments")
+	}
+
+	// A batch carries no ownership proofs, so with verification enabled only
+	// callers granted shipments:read see full details.
+	if s.ownership != nil && !canReadShipments(ctx) {
+		statuses := make([]*shippingpb.ShipmentStatus, len(resp.Shipments))
+		for i := range resp.Shipments {
+			statuses[i] = toShipmentStatus(logicv1.StatusOnly(&resp.Shipments[i]))
+		}
+		return &shippingpb.BatchTrackShipmentsResponse{Statuses: statuses, NotFound: resp.NotFound}, nil
+	}
+	return &shippingpb.BatchTrackShipmentsResponse{
+		Shipments: toShipments(resp.Shipments),
+		NotFound:  resp.NotFound,
+	}, nil
+}
+
+func (s *Server) BatchGetShipmentsByOrder(
+	ctx context.Context, req *shippingpb.BatchGetShipmentsByOrderRequest,
+) (*shippingpb.BatchGetShipmentsByOrderResponse, error) {
+	resp, err := s.service.GetShipmentsByOrderIDs(ctx, req.GetOrderIds())
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to batch get shipments by order")
+	}
+	return &shippingpb.BatchGetShipmentsByOrderResponse{
+		Shipments: toShipments(resp.Shipments),
+		NotFound:  resp.NotFound,
+	}, nil
+}
+
+func (s *Server) BatchEstimateShipping(
+	ctx context.Context, req *shippingpb.BatchEstimateShippingRequest,
+) (*shippingpb.BatchEstimateShippingResponse, error) {
+	estimates, err := s.service.EstimateShippingBatch(ctx, fromParcels(req.GetParcels()))
+	if err != nil {
+		return nil, s.toStatus(err, "Failed to batch estimate shipping")
+	}
+	msgs := make([]*shippingpb.EstimateShippingResponse, len(estimates))
+	for i := range estimates {
+		msgs[i] = toEstimate(&estimates[i])
+	}
+	return &shippingpb.BatchEstimateShippingResponse{Estimates: msgs}, nil
+}
+
+// canReadShipments reports whether the caller was granted shipments:read, which
+// shows full tracking details without an ownership proof.
+func canReadShipments(ctx context.Context) bool {
+	p, ok := principalFromContext(ctx)
+	return ok && p.HasScope(auth.ScopeShipmentsRead)
+}
+
+// toStatus maps logic errors to gRPC status codes, mirroring the HTTP handlers.
+func (s *Server) toStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, logicv1.ErrShipmentNotFound):
+		return status.Error(codes.NotFound, "shipment not found")
+	case errors.Is(err, logicv1.ErrInvalidBatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, logicv1.ErrCarrierUnavailable):
+		return status.Error(codes.Unavailable, "carrier unavailable")
+	case errors.Is(err, logicv1.ErrUnauthorized):
+		return status.Error(codes.PermissionDenied, "unauthorized")
+	default:
+		s.logger.Error(msg, zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
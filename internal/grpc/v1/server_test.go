@@ -0,0 +1,364 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/auth"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/grpc/v1/shippingpb"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeShipmentRepo serves lookups by tracking number and order; other methods are not used.
+type fakeShipmentRepo struct {
+	domain.ShipmentRepository
+	shipments map[string]domain.Shipment
+}
+
+func (f *fakeShipmentRepo) GetByTrackingNumber(_ context.Context, trackingNumber string) (*domain.Shipment, error) {
+	s, ok := f.shipments[trackingNumber]
+	if !ok {
+		return nil, fmt.Errorf("track %q: %w", trackingNumber, domain.ErrShipmentNotFound)
+	}
+	return &s, nil
+}
+
+func (f *fakeShipmentRepo) GetByTrackingNumbers(_ context.Context, trackingNumbers []string) ([]domain.Shipment, error) {
+	var found []domain.Shipment
+	for _, n := range trackingNumbers {
+		if s, ok := f.shipments[n]; ok {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeShipmentRepo) GetByOrderID(_ context.Context, orderID string) (*domain.Shipment, error) {
+	for _, s := range f.shipments {
+		if fmt.Sprint(s.OrderID) == orderID {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("get by order %q: %w", orderID, domain.ErrShipmentNotFound)
+}
+
+func (f *fakeShipmentRepo) GetByOrderIDs(_ context.Context, orderIDs []int) ([]domain.Shipment, error) {
+	var found []domain.Shipment
+	for _, s := range f.shipments {
+		if slices.Contains(orderIDs, s.OrderID) {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+// testServer configures the server under test; the zero value has
+// authentication, tracking verification and rate limiting disabled.
+type testServer struct {
+	auth      *auth.Authenticator
+	ownership *logicv1.OwnershipVerifier
+	limits    map[string]middleware.RateLimit
+}
+
+// Test API keys: readKey is granted shipments:read, otherKey no scope.
+const (
+	readKey  = "read-key"
+	otherKey = "other-key"
+)
+
+func newTestAuthenticator() *auth.Authenticator {
+	return auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{Name: "order-service", Key: readKey, Scopes: []string{auth.ScopeShipmentsRead}},
+		{Name: "ops-tool", Key: otherKey, Scopes: []string{auth.ScopeLoggingManage}},
+	}})
+}
+
+func newTestClient(t *testing.T, cfg testServer) shippingpb.ShippingServiceClient {
+	t.Helper()
+
+	repo := &fakeShipmentRepo{shipments: map[string]domain.Shipment{
+		"TRK-1": {
+			ID: 1, OrderID: 10, TrackingNumber: "TRK-1", Status: domain.StatusInTransit,
+			DestinationPostalCode: "10115",
+		},
+	}}
+	interceptors := []grpc.UnaryServerInterceptor{
+		middleware.GRPCTracingInterceptor(),
+		middleware.GRPCPrometheusInterceptor(),
+		AuthInterceptor(cfg.auth, zap.NewNop()),
+	}
+	if cfg.limits != nil {
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{Routes: cfg.limits})
+		interceptors = append(interceptors, middleware.GRPCRateLimitInterceptor(limiter, CallerSubject))
+	}
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	Register(srv, NewServer(logicv1.NewShippingService(repo), cfg.ownership, zap.NewNop()))
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return shippingpb.NewShippingServiceClient(conn)
+}
+
+func TestTrackShipment(t *testing.T) {
+	client := newTestClient(t, testServer{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		trackingNumber string
+		wantCode       codes.Code
+	}{
+		{name: "found", trackingNumber: "TRK-1", wantCode: codes.OK},
+		{name: "not found", trackingNumber: "TRK-9", wantCode: codes.NotFound},
+		{name: "missing tracking number", trackingNumber: "", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.TrackShipment(ctx, &shippingpb.TrackShipmentRequest{TrackingNumber: tt.trackingNumber})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err = %v)", code, tt.wantCode, err)
+			}
+			if got := resp.GetShipment().GetTrackingNumber(); tt.wantCode == codes.OK && got != tt.trackingNumber {
+				t.Errorf("TrackingNumber = %q, want %q", got, tt.trackingNumber)
+			}
+		})
+	}
+}
+
+func TestBatchTrackShipments(t *testing.T) {
+	client := newTestClient(t, testServer{})
+
+	resp, err := client.BatchTrackShipments(context.Background(), &shippingpb.BatchTrackShipmentsRequest{
+		TrackingNumbers: []string{"TRK-1", "TRK-9"},
+	})
+	if err != nil {
+		t.Fatalf("BatchTrackShipments() error = %v", err)
+	}
+	if len(resp.GetShipments()) != 1 || len(resp.GetNotFound()) != 1 || resp.GetNotFound()[0] != "TRK-9" {
+		t.Errorf("resp = %+v, want TRK-1 found and TRK-9 not found", resp)
+	}
+
+	_, err = client.BatchTrackShipments(context.Background(), &shippingpb.BatchTrackShipmentsRequest{})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("empty batch code = %v, want InvalidArgument", code)
+	}
+}
+
+func TestBatchEstimateShipping(t *testing.T) {
+	client := newTestClient(t, testServer{})
+
+	resp, err := client.BatchEstimateShipping(context.Background(), &shippingpb.BatchEstimateShippingRequest{
+		Parcels: []*shippingpb.EstimateShippingRequest{
+			{Origin: "NY", Destination: "NY", Weight: 2},
+			{Origin: "NY", Destination: "CA", Weight: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchEstimateShipping() error = %v", err)
+	}
+	estimates := resp.GetEstimates()
+	if len(estimates) != 2 || estimates[0].GetEstimatedCost() != 8.0 || estimates[1].GetEstimatedCost() != 18.0 {
+		t.Errorf("Estimates = %v, want costs 8 and 18", estimates)
+	}
+}
+
+// call invokes one method with a valid request, for table tests.
+type call func(ctx context.Context, opts ...grpc.CallOption) error
+
+func track(client shippingpb.ShippingServiceClient) call {
+	return func(ctx context.Context, opts ...grpc.CallOption) error {
+		_, err := client.TrackShipment(ctx, &shippingpb.TrackShipmentRequest{TrackingNumber: "TRK-1"}, opts...)
+		return err
+	}
+}
+
+func getByOrder(client shippingpb.ShippingServiceClient) call {
+	return func(ctx context.Context, opts ...grpc.CallOption) error {
+		_, err := client.GetShipmentByOrder(ctx, &shippingpb.GetShipmentByOrderRequest{OrderId: "10"}, opts...)
+		return err
+	}
+}
+
+func batchGetByOrder(client shippingpb.ShippingServiceClient) call {
+	return func(ctx context.Context, opts ...grpc.CallOption) error {
+		_, err := client.BatchGetShipmentsByOrder(ctx,
+			&shippingpb.BatchGetShipmentsByOrderRequest{OrderIds: []string{"10"}}, opts...)
+		return err
+	}
+}
+
+func batchEstimate(client shippingpb.ShippingServiceClient) call {
+	return func(ctx context.Context, opts ...grpc.CallOption) error {
+		_, err := client.BatchEstimateShipping(ctx, &shippingpb.BatchEstimateShippingRequest{
+			Parcels: []*shippingpb.EstimateShippingRequest{{Origin: "NY", Destination: "CA", Weight: 2}},
+		}, opts...)
+		return err
+	}
+}
+
+// withAPIKey sends key as x-api-key metadata; an empty key sends none.
+func withAPIKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, auth.APIKeyHeader, key)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client := newTestClient(t, testServer{auth: newTestAuthenticator()})
+
+	tests := []struct {
+		name     string
+		key      string
+		call     call
+		wantCode codes.Code
+	}{
+		{"internal without credentials", "", getByOrder(client), codes.Unauthenticated},
+		{"internal with invalid key", "made-up", getByOrder(client), codes.Unauthenticated},
+		{"internal without scope", otherKey, getByOrder(client), codes.PermissionDenied},
+		{"internal with scope", readKey, getByOrder(client), codes.OK},
+		{"internal batch without credentials", "", batchGetByOrder(client), codes.Unauthenticated},
+		{"internal batch with scope", readKey, batchGetByOrder(client), codes.OK},
+		{"public without credentials", "", track(client), codes.OK},
+		{"public with invalid key", "made-up", track(client), codes.Unauthenticated},
+		{"public without scope", otherKey, track(client), codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(withAPIKey(context.Background(), tt.key))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v (err = %v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestTrackShipmentViews(t *testing.T) {
+	verifier := logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour)
+	client := newTestClient(t, testServer{auth: newTestAuthenticator(), ownership: verifier})
+
+	tests := []struct {
+		name     string
+		key      string
+		req      *shippingpb.TrackShipmentRequest
+		wantCode codes.Code
+		wantFull bool
+	}{
+		{"no proof", "", &shippingpb.TrackShipmentRequest{}, codes.OK, false},
+		{"postal code", "", &shippingpb.TrackShipmentRequest{PostalCode: "10115"}, codes.OK, true},
+		{"wrong postal code", "", &shippingpb.TrackShipmentRequest{PostalCode: "99999"}, codes.PermissionDenied, false},
+		{
+			"customer token", "",
+			&shippingpb.TrackShipmentRequest{CustomerToken: verifier.IssueToken(10).Token}, codes.OK, true,
+		},
+		{
+			"token of another order", "",
+			&shippingpb.TrackShipmentRequest{CustomerToken: verifier.IssueToken(11).Token}, codes.PermissionDenied, false,
+		},
+		{"caller with shipments:read", readKey, &shippingpb.TrackShipmentRequest{}, codes.OK, true},
+		{"caller without shipments:read", otherKey, &shippingpb.TrackShipmentRequest{}, codes.OK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.TrackingNumber = "TRK-1"
+			resp, err := client.TrackShipment(withAPIKey(context.Background(), tt.key), tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err = %v)", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if full := resp.GetShipment() != nil; full != tt.wantFull {
+				t.Errorf("full details = %v, want %v (resp = %v)", full, tt.wantFull, resp)
+			}
+			if !tt.wantFull && resp.GetStatus().GetStatus() != domain.StatusInTransit {
+				t.Errorf("status view = %v, want status %q", resp.GetStatus(), domain.StatusInTransit)
+			}
+		})
+	}
+}
+
+func TestBatchTrackShipmentsViews(t *testing.T) {
+	verifier := logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour)
+	client := newTestClient(t, testServer{auth: newTestAuthenticator(), ownership: verifier})
+	req := &shippingpb.BatchTrackShipmentsRequest{TrackingNumbers: []string{"TRK-1", "TRK-9"}}
+
+	anonymous, err := client.BatchTrackShipments(context.Background(), req)
+	if err != nil {
+		t.Fatalf("anonymous BatchTrackShipments() error = %v", err)
+	}
+	if len(anonymous.GetShipments()) != 0 || len(anonymous.GetStatuses()) != 1 || len(anonymous.GetNotFound()) != 1 {
+		t.Errorf("anonymous resp = %v, want one status and one not found", anonymous)
+	}
+
+	internal, err := client.BatchTrackShipments(withAPIKey(context.Background(), readKey), req)
+	if err != nil {
+		t.Fatalf("internal BatchTrackShipments() error = %v", err)
+	}
+	if len(internal.GetShipments()) != 1 || len(internal.GetStatuses()) != 0 {
+		t.Errorf("internal resp = %v, want one full shipment", internal)
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	client := newTestClient(t, testServer{
+		auth: newTestAuthenticator(),
+		limits: map[string]middleware.RateLimit{
+			shippingpb.ShippingService_TrackShipment_FullMethodName: {RPS: 0.001, Burst: 1},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		key      string
+		call     call
+		wantCode codes.Code
+	}{
+		{"first anonymous call", "", track(client), codes.OK},
+		{"second anonymous call", "", track(client), codes.ResourceExhausted},
+		{"caller has its own bucket", readKey, track(client), codes.OK},
+		{"caller bucket is spent", readKey, track(client), codes.ResourceExhausted},
+		{"unlimited method", "", batchEstimate(client), codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			err := tt.call(withAPIKey(context.Background(), tt.key), grpc.Header(&header))
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err = %v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode == codes.ResourceExhausted && len(header.Get("retry-after")) == 0 {
+				t.Error("rejected call has no retry-after header")
+			}
+		})
+	}
+}
@@ -0,0 +1,1117 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: shipping/v1/shipping.proto
+
+package shippingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Shipment struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId               int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TrackingNumber        string                 `protobuf:"bytes,3,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	Carrier               string                 `protobuf:"bytes,4,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	Status                string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	EstimatedDelivery     *string                `protobuf:"bytes,6,opt,name=estimated_delivery,json=estimatedDelivery,proto3,oneof" json:"estimated_delivery,omitempty"` // RFC 3339
+	DestinationPostalCode string                 `protobuf:"bytes,7,opt,name=destination_postal_code,json=destinationPostalCode,proto3" json:"destination_postal_code,omitempty"`
+	Exception             *ShipmentException     `protobuf:"bytes,8,opt,name=exception,proto3" json:"exception,omitempty"`                   // Open delivery exception, if any
+	CreatedAt             string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`  // RFC 3339
+	UpdatedAt             string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC 3339
+	Version               int64                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`                     // Bumped on every change
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Shipment) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Shipment) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Shipment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Shipment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Shipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Shipment) GetEstimatedDelivery() string {
+	if x != nil && x.EstimatedDelivery != nil {
+		return *x.EstimatedDelivery
+	}
+	return ""
+}
+
+func (x *Shipment) GetDestinationPostalCode() string {
+	if x != nil {
+		return x.DestinationPostalCode
+	}
+	return ""
+}
+
+func (x *Shipment) GetException() *ShipmentException {
+	if x != nil {
+		return x.Exception
+	}
+	return nil
+}
+
+func (x *Shipment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Shipment) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Shipment) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ShipmentException struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShipmentId    int64                  `protobuf:"varint,2,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	ReasonCode    string                 `protobuf:"bytes,3,opt,name=reason_code,json=reasonCode,proto3" json:"reason_code,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentException) Reset() {
+	*x = ShipmentException{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentException) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentException) ProtoMessage() {}
+
+func (x *ShipmentException) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentException.ProtoReflect.Descriptor instead.
+func (*ShipmentException) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShipmentException) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ShipmentException) GetShipmentId() int64 {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return 0
+}
+
+func (x *ShipmentException) GetReasonCode() string {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ""
+}
+
+func (x *ShipmentException) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ShipmentException) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// ShipmentStatus is the tracking view for callers that have not proven
+// ownership of the shipment.
+type ShipmentStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TrackingNumber string                 `protobuf:"bytes,1,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShipmentStatus) Reset() {
+	*x = ShipmentStatus{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentStatus) ProtoMessage() {}
+
+func (x *ShipmentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentStatus.ProtoReflect.Descriptor instead.
+func (*ShipmentStatus) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ShipmentStatus) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *ShipmentStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type TrackShipmentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TrackingNumber string                 `protobuf:"bytes,1,opt,name=tracking_number,json=trackingNumber,proto3" json:"tracking_number,omitempty"`
+	// Ownership proof, as on the HTTP tracking route: the destination postal
+	// code, or a customer token issued for the order.
+	PostalCode    string `protobuf:"bytes,2,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	CustomerToken string `protobuf:"bytes,3,opt,name=customer_token,json=customerToken,proto3" json:"customer_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackShipmentRequest) Reset() {
+	*x = TrackShipmentRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackShipmentRequest) ProtoMessage() {}
+
+func (x *TrackShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackShipmentRequest.ProtoReflect.Descriptor instead.
+func (*TrackShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TrackShipmentRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *TrackShipmentRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *TrackShipmentRequest) GetCustomerToken() string {
+	if x != nil {
+		return x.CustomerToken
+	}
+	return ""
+}
+
+type TrackShipmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to View:
+	//
+	//	*TrackShipmentResponse_Shipment
+	//	*TrackShipmentResponse_Status
+	View          isTrackShipmentResponse_View `protobuf_oneof:"view"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackShipmentResponse) Reset() {
+	*x = TrackShipmentResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackShipmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackShipmentResponse) ProtoMessage() {}
+
+func (x *TrackShipmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackShipmentResponse.ProtoReflect.Descriptor instead.
+func (*TrackShipmentResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TrackShipmentResponse) GetView() isTrackShipmentResponse_View {
+	if x != nil {
+		return x.View
+	}
+	return nil
+}
+
+func (x *TrackShipmentResponse) GetShipment() *Shipment {
+	if x != nil {
+		if x, ok := x.View.(*TrackShipmentResponse_Shipment); ok {
+			return x.Shipment
+		}
+	}
+	return nil
+}
+
+func (x *TrackShipmentResponse) GetStatus() *ShipmentStatus {
+	if x != nil {
+		if x, ok := x.View.(*TrackShipmentResponse_Status); ok {
+			return x.Status
+		}
+	}
+	return nil
+}
+
+type isTrackShipmentResponse_View interface {
+	isTrackShipmentResponse_View()
+}
+
+type TrackShipmentResponse_Shipment struct {
+	Shipment *Shipment `protobuf:"bytes,1,opt,name=shipment,proto3,oneof"` // Full details
+}
+
+type TrackShipmentResponse_Status struct {
+	Status *ShipmentStatus `protobuf:"bytes,2,opt,name=status,proto3,oneof"` // Without ownership proof
+}
+
+func (*TrackShipmentResponse_Shipment) isTrackShipmentResponse_View() {}
+
+func (*TrackShipmentResponse_Status) isTrackShipmentResponse_View() {}
+
+type GetShipmentByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentByOrderRequest) Reset() {
+	*x = GetShipmentByOrderRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentByOrderRequest) ProtoMessage() {}
+
+func (x *GetShipmentByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentByOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetShipmentByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetShipmentByOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetShipmentByOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shipment      *Shipment              `protobuf:"bytes,1,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentByOrderResponse) Reset() {
+	*x = GetShipmentByOrderResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentByOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentByOrderResponse) ProtoMessage() {}
+
+func (x *GetShipmentByOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentByOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetShipmentByOrderResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetShipmentByOrderResponse) GetShipment() *Shipment {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+type EstimateShippingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Weight        float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateShippingRequest) Reset() {
+	*x = EstimateShippingRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateShippingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateShippingRequest) ProtoMessage() {}
+
+func (x *EstimateShippingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateShippingRequest.ProtoReflect.Descriptor instead.
+func (*EstimateShippingRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EstimateShippingRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *EstimateShippingRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *EstimateShippingRequest) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type EstimateShippingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Weight        float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	EstimatedCost float64                `protobuf:"fixed64,4,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	EstimatedDays int32                  `protobuf:"varint,5,opt,name=estimated_days,json=estimatedDays,proto3" json:"estimated_days,omitempty"`
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Carrier       string                 `protobuf:"bytes,7,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateShippingResponse) Reset() {
+	*x = EstimateShippingResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateShippingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateShippingResponse) ProtoMessage() {}
+
+func (x *EstimateShippingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateShippingResponse.ProtoReflect.Descriptor instead.
+func (*EstimateShippingResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EstimateShippingResponse) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *EstimateShippingResponse) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *EstimateShippingResponse) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *EstimateShippingResponse) GetEstimatedCost() float64 {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return 0
+}
+
+func (x *EstimateShippingResponse) GetEstimatedDays() int32 {
+	if x != nil {
+		return x.EstimatedDays
+	}
+	return 0
+}
+
+func (x *EstimateShippingResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *EstimateShippingResponse) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+type BatchTrackShipmentsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TrackingNumbers []string               `protobuf:"bytes,1,rep,name=tracking_numbers,json=trackingNumbers,proto3" json:"tracking_numbers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BatchTrackShipmentsRequest) Reset() {
+	*x = BatchTrackShipmentsRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTrackShipmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTrackShipmentsRequest) ProtoMessage() {}
+
+func (x *BatchTrackShipmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTrackShipmentsRequest.ProtoReflect.Descriptor instead.
+func (*BatchTrackShipmentsRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchTrackShipmentsRequest) GetTrackingNumbers() []string {
+	if x != nil {
+		return x.TrackingNumbers
+	}
+	return nil
+}
+
+type BatchTrackShipmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shipments     []*Shipment            `protobuf:"bytes,1,rep,name=shipments,proto3" json:"shipments,omitempty"`               // Full details, for callers allowed to see them
+	NotFound      []string               `protobuf:"bytes,2,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"` // Requested tracking numbers that matched no shipment
+	Statuses      []*ShipmentStatus      `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`                 // Set instead of shipments for other callers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchTrackShipmentsResponse) Reset() {
+	*x = BatchTrackShipmentsResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTrackShipmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTrackShipmentsResponse) ProtoMessage() {}
+
+func (x *BatchTrackShipmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTrackShipmentsResponse.ProtoReflect.Descriptor instead.
+func (*BatchTrackShipmentsResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchTrackShipmentsResponse) GetShipments() []*Shipment {
+	if x != nil {
+		return x.Shipments
+	}
+	return nil
+}
+
+func (x *BatchTrackShipmentsResponse) GetNotFound() []string {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+func (x *BatchTrackShipmentsResponse) GetStatuses() []*ShipmentStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type BatchGetShipmentsByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderIds      []string               `protobuf:"bytes,1,rep,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetShipmentsByOrderRequest) Reset() {
+	*x = BatchGetShipmentsByOrderRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetShipmentsByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetShipmentsByOrderRequest) ProtoMessage() {}
+
+func (x *BatchGetShipmentsByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetShipmentsByOrderRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetShipmentsByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetShipmentsByOrderRequest) GetOrderIds() []string {
+	if x != nil {
+		return x.OrderIds
+	}
+	return nil
+}
+
+type BatchGetShipmentsByOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shipments     []*Shipment            `protobuf:"bytes,1,rep,name=shipments,proto3" json:"shipments,omitempty"`
+	NotFound      []string               `protobuf:"bytes,2,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"` // Requested order IDs that matched no shipment
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetShipmentsByOrderResponse) Reset() {
+	*x = BatchGetShipmentsByOrderResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetShipmentsByOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetShipmentsByOrderResponse) ProtoMessage() {}
+
+func (x *BatchGetShipmentsByOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetShipmentsByOrderResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetShipmentsByOrderResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchGetShipmentsByOrderResponse) GetShipments() []*Shipment {
+	if x != nil {
+		return x.Shipments
+	}
+	return nil
+}
+
+func (x *BatchGetShipmentsByOrderResponse) GetNotFound() []string {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+type BatchEstimateShippingRequest struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Parcels       []*EstimateShippingRequest `protobuf:"bytes,1,rep,name=parcels,proto3" json:"parcels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEstimateShippingRequest) Reset() {
+	*x = BatchEstimateShippingRequest{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEstimateShippingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEstimateShippingRequest) ProtoMessage() {}
+
+func (x *BatchEstimateShippingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEstimateShippingRequest.ProtoReflect.Descriptor instead.
+func (*BatchEstimateShippingRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchEstimateShippingRequest) GetParcels() []*EstimateShippingRequest {
+	if x != nil {
+		return x.Parcels
+	}
+	return nil
+}
+
+type BatchEstimateShippingResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Estimates     []*EstimateShippingResponse `protobuf:"bytes,1,rep,name=estimates,proto3" json:"estimates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEstimateShippingResponse) Reset() {
+	*x = BatchEstimateShippingResponse{}
+	mi := &file_shipping_v1_shipping_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEstimateShippingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEstimateShippingResponse) ProtoMessage() {}
+
+func (x *BatchEstimateShippingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_v1_shipping_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEstimateShippingResponse.ProtoReflect.Descriptor instead.
+func (*BatchEstimateShippingResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_v1_shipping_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchEstimateShippingResponse) GetEstimates() []*EstimateShippingResponse {
+	if x != nil {
+		return x.Estimates
+	}
+	return nil
+}
+
+var File_shipping_v1_shipping_proto protoreflect.FileDescriptor
+
+const file_shipping_v1_shipping_proto_rawDesc = "" +
+	"\n" +
+	"\x1ashipping/v1/shipping.proto\x12\vshipping.v1\"\xa9\x03\n" +
+	"\bShipment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12'\n" +
+	"\x0ftracking_number\x18\x03 \x01(\tR\x0etrackingNumber\x12\x18\n" +
+	"\acarrier\x18\x04 \x01(\tR\acarrier\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x122\n" +
+	"\x12estimated_delivery\x18\x06 \x01(\tH\x00R\x11estimatedDelivery\x88\x01\x01\x126\n" +
+	"\x17destination_postal_code\x18\a \x01(\tR\x15destinationPostalCode\x12<\n" +
+	"\texception\x18\b \x01(\v2\x1e.shipping.v1.ShipmentExceptionR\texception\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\v \x01(\x03R\aversionB\x15\n" +
+	"\x13_estimated_delivery\"\xa6\x01\n" +
+	"\x11ShipmentException\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vshipment_id\x18\x02 \x01(\x03R\n" +
+	"shipmentId\x12\x1f\n" +
+	"\vreason_code\x18\x03 \x01(\tR\n" +
+	"reasonCode\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"Q\n" +
+	"\x0eShipmentStatus\x12'\n" +
+	"\x0ftracking_number\x18\x01 \x01(\tR\x0etrackingNumber\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"\x87\x01\n" +
+	"\x14TrackShipmentRequest\x12'\n" +
+	"\x0ftracking_number\x18\x01 \x01(\tR\x0etrackingNumber\x12\x1f\n" +
+	"\vpostal_code\x18\x02 \x01(\tR\n" +
+	"postalCode\x12%\n" +
+	"\x0ecustomer_token\x18\x03 \x01(\tR\rcustomerToken\"\x8b\x01\n" +
+	"\x15TrackShipmentResponse\x123\n" +
+	"\bshipment\x18\x01 \x01(\v2\x15.shipping.v1.ShipmentH\x00R\bshipment\x125\n" +
+	"\x06status\x18\x02 \x01(\v2\x1b.shipping.v1.ShipmentStatusH\x00R\x06statusB\x06\n" +
+	"\x04view\"6\n" +
+	"\x19GetShipmentByOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"O\n" +
+	"\x1aGetShipmentByOrderResponse\x121\n" +
+	"\bshipment\x18\x01 \x01(\v2\x15.shipping.v1.ShipmentR\bshipment\"k\n" +
+	"\x17EstimateShippingRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\"\xf0\x01\n" +
+	"\x18EstimateShippingResponse\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\x12%\n" +
+	"\x0eestimated_cost\x18\x04 \x01(\x01R\restimatedCost\x12%\n" +
+	"\x0eestimated_days\x18\x05 \x01(\x05R\restimatedDays\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x18\n" +
+	"\acarrier\x18\a \x01(\tR\acarrier\"G\n" +
+	"\x1aBatchTrackShipmentsRequest\x12)\n" +
+	"\x10tracking_numbers\x18\x01 \x03(\tR\x0ftrackingNumbers\"\xa8\x01\n" +
+	"\x1bBatchTrackShipmentsResponse\x123\n" +
+	"\tshipments\x18\x01 \x03(\v2\x15.shipping.v1.ShipmentR\tshipments\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x03(\tR\bnotFound\x127\n" +
+	"\bstatuses\x18\x03 \x03(\v2\x1b.shipping.v1.ShipmentStatusR\bstatuses\">\n" +
+	"\x1fBatchGetShipmentsByOrderRequest\x12\x1b\n" +
+	"\torder_ids\x18\x01 \x03(\tR\borderIds\"t\n" +
+	" BatchGetShipmentsByOrderResponse\x123\n" +
+	"\tshipments\x18\x01 \x03(\v2\x15.shipping.v1.ShipmentR\tshipments\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x03(\tR\bnotFound\"^\n" +
+	"\x1cBatchEstimateShippingRequest\x12>\n" +
+	"\aparcels\x18\x01 \x03(\v2$.shipping.v1.EstimateShippingRequestR\aparcels\"d\n" +
+	"\x1dBatchEstimateShippingResponse\x12C\n" +
+	"\testimates\x18\x01 \x03(\v2%.shipping.v1.EstimateShippingResponseR\testimates2\x84\x05\n" +
+	"\x0fShippingService\x12V\n" +
+	"\rTrackShipment\x12!.shipping.v1.TrackShipmentRequest\x1a\".shipping.v1.TrackShipmentResponse\x12e\n" +
+	"\x12GetShipmentByOrder\x12&.shipping.v1.GetShipmentByOrderRequest\x1a'.shipping.v1.GetShipmentByOrderResponse\x12_\n" +
+	"\x10EstimateShipping\x12$.shipping.v1.EstimateShippingRequest\x1a%.shipping.v1.EstimateShippingResponse\x12h\n" +
+	"\x13BatchTrackShipments\x12'.shipping.v1.BatchTrackShipmentsRequest\x1a(.shipping.v1.BatchTrackShipmentsResponse\x12w\n" +
+	"\x18BatchGetShipmentsByOrder\x12,.shipping.v1.BatchGetShipmentsByOrderRequest\x1a-.shipping.v1.BatchGetShipmentsByOrderResponse\x12n\n" +
+	"\x15BatchEstimateShipping\x12).shipping.v1.BatchEstimateShippingRequest\x1a*.shipping.v1.BatchEstimateShippingResponseBAZ?github.com/duynhne/shipping-service/internal/grpc/v1/shippingpbb\x06proto3"
+
+var (
+	file_shipping_v1_shipping_proto_rawDescOnce sync.Once
+	file_shipping_v1_shipping_proto_rawDescData []byte
+)
+
+func file_shipping_v1_shipping_proto_rawDescGZIP() []byte {
+	file_shipping_v1_shipping_proto_rawDescOnce.Do(func() {
+		file_shipping_v1_shipping_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shipping_v1_shipping_proto_rawDesc), len(file_shipping_v1_shipping_proto_rawDesc)))
+	})
+	return file_shipping_v1_shipping_proto_rawDescData
+}
+
+var file_shipping_v1_shipping_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_shipping_v1_shipping_proto_goTypes = []any{
+	(*Shipment)(nil),                         // 0: shipping.v1.Shipment
+	(*ShipmentException)(nil),                // 1: shipping.v1.ShipmentException
+	(*ShipmentStatus)(nil),                   // 2: shipping.v1.ShipmentStatus
+	(*TrackShipmentRequest)(nil),             // 3: shipping.v1.TrackShipmentRequest
+	(*TrackShipmentResponse)(nil),            // 4: shipping.v1.TrackShipmentResponse
+	(*GetShipmentByOrderRequest)(nil),        // 5: shipping.v1.GetShipmentByOrderRequest
+	(*GetShipmentByOrderResponse)(nil),       // 6: shipping.v1.GetShipmentByOrderResponse
+	(*EstimateShippingRequest)(nil),          // 7: shipping.v1.EstimateShippingRequest
+	(*EstimateShippingResponse)(nil),         // 8: shipping.v1.EstimateShippingResponse
+	(*BatchTrackShipmentsRequest)(nil),       // 9: shipping.v1.BatchTrackShipmentsRequest
+	(*BatchTrackShipmentsResponse)(nil),      // 10: shipping.v1.BatchTrackShipmentsResponse
+	(*BatchGetShipmentsByOrderRequest)(nil),  // 11: shipping.v1.BatchGetShipmentsByOrderRequest
+	(*BatchGetShipmentsByOrderResponse)(nil), // 12: shipping.v1.BatchGetShipmentsByOrderResponse
+	(*BatchEstimateShippingRequest)(nil),     // 13: shipping.v1.BatchEstimateShippingRequest
+	(*BatchEstimateShippingResponse)(nil),    // 14: shipping.v1.BatchEstimateShippingResponse
+}
+var file_shipping_v1_shipping_proto_depIdxs = []int32{
+	1,  // 0: shipping.v1.Shipment.exception:type_name -> shipping.v1.ShipmentException
+	0,  // 1: shipping.v1.TrackShipmentResponse.shipment:type_name -> shipping.v1.Shipment
+	2,  // 2: shipping.v1.TrackShipmentResponse.status:type_name -> shipping.v1.ShipmentStatus
+	0,  // 3: shipping.v1.GetShipmentByOrderResponse.shipment:type_name -> shipping.v1.Shipment
+	0,  // 4: shipping.v1.BatchTrackShipmentsResponse.shipments:type_name -> shipping.v1.Shipment
+	2,  // 5: shipping.v1.BatchTrackShipmentsResponse.statuses:type_name -> shipping.v1.ShipmentStatus
+	0,  // 6: shipping.v1.BatchGetShipmentsByOrderResponse.shipments:type_name -> shipping.v1.Shipment
+	7,  // 7: shipping.v1.BatchEstimateShippingRequest.parcels:type_name -> shipping.v1.EstimateShippingRequest
+	8,  // 8: shipping.v1.BatchEstimateShippingResponse.estimates:type_name -> shipping.v1.EstimateShippingResponse
+	3,  // 9: shipping.v1.ShippingService.TrackShipment:input_type -> shipping.v1.TrackShipmentRequest
+	5,  // 10: shipping.v1.ShippingService.GetShipmentByOrder:input_type -> shipping.v1.GetShipmentByOrderRequest
+	7,  // 11: shipping.v1.ShippingService.EstimateShipping:input_type -> shipping.v1.EstimateShippingRequest
+	9,  // 12: shipping.v1.ShippingService.BatchTrackShipments:input_type -> shipping.v1.BatchTrackShipmentsRequest
+	11, // 13: shipping.v1.ShippingService.BatchGetShipmentsByOrder:input_type -> shipping.v1.BatchGetShipmentsByOrderRequest
+	13, // 14: shipping.v1.ShippingService.BatchEstimateShipping:input_type -> shipping.v1.BatchEstimateShippingRequest
+	4,  // 15: shipping.v1.ShippingService.TrackShipment:output_type -> shipping.v1.TrackShipmentResponse
+	6,  // 16: shipping.v1.ShippingService.GetShipmentByOrder:output_type -> shipping.v1.GetShipmentByOrderResponse
+	8,  // 17: shipping.v1.ShippingService.EstimateShipping:output_type -> shipping.v1.EstimateShippingResponse
+	10, // 18: shipping.v1.ShippingService.BatchTrackShipments:output_type -> shipping.v1.BatchTrackShipmentsResponse
+	12, // 19: shipping.v1.ShippingService.BatchGetShipmentsByOrder:output_type -> shipping.v1.BatchGetShipmentsByOrderResponse
+	14, // 20: shipping.v1.ShippingService.BatchEstimateShipping:output_type -> shipping.v1.BatchEstimateShippingResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_shipping_v1_shipping_proto_init() }
+func file_shipping_v1_shipping_proto_init() {
+	if File_shipping_v1_shipping_proto != nil {
+		return
+	}
+	file_shipping_v1_shipping_proto_msgTypes[0].OneofWrappers = []any{}
+	file_shipping_v1_shipping_proto_msgTypes[4].OneofWrappers = []any{
+		(*TrackShipmentResponse_Shipment)(nil),
+		(*TrackShipmentResponse_Status)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shipping_v1_shipping_proto_rawDesc), len(file_shipping_v1_shipping_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_shipping_v1_shipping_proto_goTypes,
+		DependencyIndexes: file_shipping_v1_shipping_proto_depIdxs,
+		MessageInfos:      file_shipping_v1_shipping_proto_msgTypes,
+	}.Build()
+	File_shipping_v1_shipping_proto = out.File
+	file_shipping_v1_shipping_proto_goTypes = nil
+	file_shipping_v1_shipping_proto_depIdxs = nil
+}
@@ -0,0 +1,351 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             (unknown)
+// source: shipping/v1/shipping.proto
+
+package shippingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ShippingService_TrackShipment_FullMethodName            = "/shipping.v1.ShippingService/TrackShipment"
+	ShippingService_GetShipmentByOrder_FullMethodName       = "/shipping.v1.ShippingService/GetShipmentByOrder"
+	ShippingService_EstimateShipping_FullMethodName         = "/shipping.v1.ShippingService/EstimateShipping"
+	ShippingService_BatchTrackShipments_FullMethodName      = "/shipping.v1.ShippingService/BatchTrackShipments"
+	ShippingService_BatchGetShipmentsByOrder_FullMethodName = "/shipping.v1.ShippingService/BatchGetShipmentsByOrder"
+	ShippingService_BatchEstimateShipping_FullMethodName    = "/shipping.v1.ShippingService/BatchEstimateShipping"
+)
+
+// ShippingServiceClient is the client API for ShippingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ShippingService is the read API of shipping-service for other services.
+// It is backed by the same business logic as the HTTP API, and field names
+// match the HTTP JSON fields.
+//
+// Callers authenticate like on the HTTP internal routes, with
+// "authorization: Bearer <jwt>" or "x-api-key" metadata. Internal methods
+// require the shipments:read scope. Public methods also accept anonymous
+// callers and are rate limited.
+//
+// Batches accept up to 100 keys. Unknown keys are listed in not_found
+// instead of failing the call.
+type ShippingServiceClient interface {
+	// Public. With tracking verification enabled, full details need
+	// shipments:read or an ownership proof.
+	TrackShipment(ctx context.Context, in *TrackShipmentRequest, opts ...grpc.CallOption) (*TrackShipmentResponse, error)
+	// Internal.
+	GetShipmentByOrder(ctx context.Context, in *GetShipmentByOrderRequest, opts ...grpc.CallOption) (*GetShipmentByOrderResponse, error)
+	// Public.
+	EstimateShipping(ctx context.Context, in *EstimateShippingRequest, opts ...grpc.CallOption) (*EstimateShippingResponse, error)
+	// Public. With tracking verification enabled, callers without
+	// shipments:read get statuses only.
+	BatchTrackShipments(ctx context.Context, in *BatchTrackShipmentsRequest, opts ...grpc.CallOption) (*BatchTrackShipmentsResponse, error)
+	// Internal.
+	BatchGetShipmentsByOrder(ctx context.Context, in *BatchGetShipmentsByOrderRequest, opts ...grpc.CallOption) (*BatchGetShipmentsByOrderResponse, error)
+	// Public.
+	BatchEstimateShipping(ctx context.Context, in *BatchEstimateShippingRequest, opts ...grpc.CallOption) (*BatchEstimateShippingResponse, error)
+}
+
+type shippingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShippingServiceClient(cc grpc.ClientConnInterface) ShippingServiceClient {
+	return &shippingServiceClient{cc}
+}
+
+func (c *shippingServiceClient) TrackShipment(ctx context.Context, in *TrackShipmentRequest, opts ...grpc.CallOption) (*TrackShipmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrackShipmentResponse)
+	err := c.cc.Invoke(ctx, ShippingService_TrackShipment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shippingServiceClient) GetShipmentByOrder(ctx context.Context, in *GetShipmentByOrderRequest, opts ...grpc.CallOption) (*GetShipmentByOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetShipmentByOrderResponse)
+	err := c.cc.Invoke(ctx, ShippingService_GetShipmentByOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shippingServiceClient) EstimateShipping(ctx context.Context, in *EstimateShippingRequest, opts ...grpc.CallOption) (*EstimateShippingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateShippingResponse)
+	err := c.cc.Invoke(ctx, ShippingService_EstimateShipping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shippingServiceClient) BatchTrackShipments(ctx context.Context, in *BatchTrackShipmentsRequest, opts ...grpc.CallOption) (*BatchTrackShipmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchTrackShipmentsResponse)
+	err := c.cc.Invoke(ctx, ShippingService_BatchTrackShipments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shippingServiceClient) BatchGetShipmentsByOrder(ctx context.Context, in *BatchGetShipmentsByOrderRequest, opts ...grpc.CallOption) (*BatchGetShipmentsByOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetShipmentsByOrderResponse)
+	err := c.cc.Invoke(ctx, ShippingService_BatchGetShipmentsByOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shippingServiceClient) BatchEstimateShipping(ctx context.Context, in *BatchEstimateShippingRequest, opts ...grpc.CallOption) (*BatchEstimateShippingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchEstimateShippingResponse)
+	err := c.cc.Invoke(ctx, ShippingService_BatchEstimateShipping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShippingServiceServer is the server API for ShippingService service.
+// All implementations must embed UnimplementedShippingServiceServer
+// for forward compatibility.
+//
+// ShippingService is the read API of shipping-service for other services.
+// It is backed by the same business logic as the HTTP API, and field names
+// match the HTTP JSON fields.
+//
+// Callers authenticate like on the HTTP internal routes, with
+// "authorization: Bearer <jwt>" or "x-api-key" metadata. Internal methods
+// require the shipments:read scope. Public methods also accept anonymous
+// callers and are rate limited.
+//
+// Batches accept up to 100 keys. Unknown keys are listed in not_found
+// instead of failing the call.
+type ShippingServiceServer interface {
+	// Public. With tracking verification enabled, full details need
+	// shipments:read or an ownership proof.
+	TrackShipment(context.Context, *TrackShipmentRequest) (*TrackShipmentResponse, error)
+	// Internal.
+	GetShipmentByOrder(context.Context, *GetShipmentByOrderRequest) (*GetShipmentByOrderResponse, error)
+	// Public.
+	EstimateShipping(context.Context, *EstimateShippingRequest) (*EstimateShippingResponse, error)
+	// Public. With tracking verification enabled, callers without
+	// shipments:read get statuses only.
+	BatchTrackShipments(context.Context, *BatchTrackShipmentsRequest) (*BatchTrackShipmentsResponse, error)
+	// Internal.
+	BatchGetShipmentsByOrder(context.Context, *BatchGetShipmentsByOrderRequest) (*BatchGetShipmentsByOrderResponse, error)
+	// Public.
+	BatchEstimateShipping(context.Context, *BatchEstimateShippingRequest) (*BatchEstimateShippingResponse, error)
+	mustEmbedUnimplementedShippingServiceServer()
+}
+
+// UnimplementedShippingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedShippingServiceServer struct{}
+
+func (UnimplementedShippingServiceServer) TrackShipment(context.Context, *TrackShipmentRequest) (*TrackShipmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TrackShipment not implemented")
+}
+func (UnimplementedShippingServiceServer) GetShipmentByOrder(context.Context, *GetShipmentByOrderRequest) (*GetShipmentByOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetShipmentByOrder not implemented")
+}
+func (UnimplementedShippingServiceServer) EstimateShipping(context.Context, *EstimateShippingRequest) (*EstimateShippingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EstimateShipping not implemented")
+}
+func (UnimplementedShippingServiceServer) BatchTrackShipments(context.Context, *BatchTrackShipmentsRequest) (*BatchTrackShipmentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchTrackShipments not implemented")
+}
+func (UnimplementedShippingServiceServer) BatchGetShipmentsByOrder(context.Context, *BatchGetShipmentsByOrderRequest) (*BatchGetShipmentsByOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetShipmentsByOrder not implemented")
+}
+func (UnimplementedShippingServiceServer) BatchEstimateShipping(context.Context, *BatchEstimateShippingRequest) (*BatchEstimateShippingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchEstimateShipping not implemented")
+}
+func (UnimplementedShippingServiceServer) mustEmbedUnimplementedShippingServiceServer() {}
+func (UnimplementedShippingServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeShippingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShippingServiceServer will
+// result in compilation errors.
+type UnsafeShippingServiceServer interface {
+	mustEmbedUnimplementedShippingServiceServer()
+}
+
+func RegisterShippingServiceServer(s grpc.ServiceRegistrar, srv ShippingServiceServer) {
+	// If the following call panics, it indicates UnimplementedShippingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ShippingService_ServiceDesc, srv)
+}
+
+func _ShippingService_TrackShipment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackShipmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).TrackShipment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_TrackShipment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).TrackShipment(ctx, req.(*TrackShipmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShippingService_GetShipmentByOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShipmentByOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).GetShipmentByOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_GetShipmentByOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).GetShipmentByOrder(ctx, req.(*GetShipmentByOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShippingService_EstimateShipping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateShippingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).EstimateShipping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_EstimateShipping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).EstimateShipping(ctx, req.(*EstimateShippingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShippingService_BatchTrackShipments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchTrackShipmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).BatchTrackShipments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_BatchTrackShipments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).BatchTrackShipments(ctx, req.(*BatchTrackShipmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShippingService_BatchGetShipmentsByOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetShipmentsByOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).BatchGetShipmentsByOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_BatchGetShipmentsByOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).BatchGetShipmentsByOrder(ctx, req.(*BatchGetShipmentsByOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShippingService_BatchEstimateShipping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchEstimateShippingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShippingServiceServer).BatchEstimateShipping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShippingService_BatchEstimateShipping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShippingServiceServer).BatchEstimateShipping(ctx, req.(*BatchEstimateShippingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ShippingService_ServiceDesc is the grpc.ServiceDesc for ShippingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ShippingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shipping.v1.ShippingService",
+	HandlerType: (*ShippingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TrackShipment",
+			Handler:    _ShippingService_TrackShipment_Handler,
+		},
+		{
+			MethodName: "GetShipmentByOrder",
+			Handler:    _ShippingService_GetShipmentByOrder_Handler,
+		},
+		{
+			MethodName: "EstimateShipping",
+			Handler:    _ShippingService_EstimateShipping_Handler,
+		},
+		{
+			MethodName: "BatchTrackShipments",
+			Handler:    _ShippingService_BatchTrackShipments_Handler,
+		},
+		{
+			MethodName: "BatchGetShipmentsByOrder",
+			Handler:    _ShippingService_BatchGetShipmentsByOrder_Handler,
+		},
+		{
+			MethodName: "BatchEstimateShipping",
+			Handler:    _ShippingService_BatchEstimateShipping_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shipping/v1/shipping.proto",
+}
@@ -0,0 +1,18 @@
+package health
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	checkUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "readiness_check_up",
+		Help: "Whether the last run of a readiness check passed (1) or failed (0).",
+	}, []string{"check"})
+
+	checkFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "readiness_check_failures_total",
+		Help: "Readiness check runs that failed, including timeouts.",
+	}, []string{"check"})
+)
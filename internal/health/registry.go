@@ -0,0 +1,171 @@
+// Package health aggregates dependency checks for the readiness probe.
+//
+// Components register a Check with the Registry: the database ping, read
+// replicas, the outbox relay lag and, as they are added, carrier adapters.
+// Run executes all checks concurrently, each under its own timeout, and caches
+// every result for a short time so frequent probes cannot hammer a dependency.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status values reported for a check and for a whole Report.
+const (
+	StatusOK          = "ok"
+	StatusFail        = "fail"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// defaultTimeout bounds a check registered without its own Timeout.
+const defaultTimeout = 2 * time.Second
+
+// Check is one dependency check. Func returns nil when the dependency is usable.
+type Check struct {
+	Name    string
+	Func    func(ctx context.Context) error
+	Timeout time.Duration // Zero uses the registry default
+	// Critical checks make the service unready when they fail. Other failures
+	// only mark the report degraded; they cover problems that taking the pod
+	// out of rotation would not fix, such as a slow outbox consumer.
+	Critical bool
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status     string    `json:"status"`
+	Critical   bool      `json:"critical"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Report aggregates all check results. Status is "ok" when every check
+// passed, "degraded" when only non-critical checks failed and "unavailable"
+// when a critical check failed.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether the service should receive traffic.
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Registry holds the registered checks and their cached results.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	checks []*entry
+}
+
+// entry serializes runs of one check, so concurrent probes share a result.
+type entry struct {
+	Check
+
+	mu     sync.Mutex
+	result Result
+	valid  bool
+}
+
+// NewRegistry creates a registry. timeout applies to checks without their
+// own; results are reused for cacheTTL.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Registry{timeout: timeout, cacheTTL: cacheTTL, now: time.Now}
+}
+
+// Register adds a check. Names must be unique.
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = r.timeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &entry{Check: check})
+}
+
+// Run executes every check whose cached result has expired and returns the report.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := append([]*entry(nil), r.checks...)
+	r.mu.Unlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, e := range checks {
+		wg.Go(func() { results[i] = r.run(ctx, e) })
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, e := range checks {
+		res := results[i]
+		report.Checks[e.Name] = res
+		if res.Status == StatusOK {
+			continue
+		}
+		if e.Critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	start := r.now()
+	if e.valid && start.Sub(e.result.CheckedAt) < r.cacheTTL {
+		return e.result
+	}
+
+	// The result is shared and cached, so a probe that hangs up must not fail it.
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.Timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- e.Func(checkCtx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		// Do not wait for a check that ignores its context.
+		err = fmt.Errorf("timed out after %s", e.Timeout)
+	}
+
+	res := Result{
+		Status:     StatusOK,
+		Critical:   e.Critical,
+		DurationMS: r.now().Sub(start).Milliseconds(),
+		CheckedAt:  start,
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+		checkFailures.WithLabelValues(e.Name).Inc()
+	}
+	checkUp.WithLabelValues(e.Name).Set(boolToFloat(err == nil))
+
+	e.result, e.valid = res, true
+	return res
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func pass(context.Context) error { return nil }
+
+func fail(context.Context) error { return errors.New("down") }
+
+func TestRegistryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantStatus string
+		wantReady  bool
+	}{
+		{"no checks", nil, StatusOK, true},
+		{
+			"all pass",
+			[]Check{{Name: "db", Func: pass, Critical: true}, {Name: "lag", Func: pass}},
+			StatusOK, true,
+		},
+		{
+			"non-critical failure degrades",
+			[]Check{{Name: "db", Func: pass, Critical: true}, {Name: "lag", Func: fail}},
+			StatusDegraded, true,
+		},
+		{
+			"critical failure is unavailable",
+			[]Check{{Name: "db", Func: fail, Critical: true}, {Name: "lag", Func: fail}},
+			StatusUnavailable, false,
+		},
+		{
+			"timeout fails the check",
+			[]Check{{
+				Name:     "db",
+				Critical: true,
+				Timeout:  10 * time.Millisecond,
+				Func:     func(context.Context) error { time.Sleep(time.Second); return nil },
+			}},
+			StatusUnavailable, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(time.Second, 0)
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+			report := r.Run(context.Background())
+
+			if report.Status != tt.wantStatus || report.Ready() != tt.wantReady {
+				t.Errorf("Run() = %s (ready %v), want %s (ready %v)",
+					report.Status, report.Ready(), tt.wantStatus, tt.wantReady)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("report has %d checks, want %d", len(report.Checks), len(tt.checks))
+			}
+			for _, c := range tt.checks {
+				if res := report.Checks[c.Name]; res.Status == StatusFail && res.Error == "" {
+					t.Errorf("check %s failed without an error", c.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRegistry(time.Second, 5*time.Second)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	r.Register(Check{Name: "db", Critical: true, Func: func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}})
+
+	r.Run(context.Background())
+	r.Run(context.Background())
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls within TTL = %d, want 1", got)
+	}
+
+	now = now.Add(5 * time.Second)
+	r.Run(context.Background())
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls after TTL = %d, want 2", got)
+	}
+}
+
+func TestRegistryIgnoresProbeCancellation(t *testing.T) {
+	r := NewRegistry(time.Second, time.Minute)
+	r.Register(Check{Name: "db", Critical: true, Func: func(ctx context.Context) error { return ctx.Err() }})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if report := r.Run(ctx); !report.Ready() {
+		t.Errorf("a cancelled probe failed the check: %+v", report.Checks["db"])
+	}
+}
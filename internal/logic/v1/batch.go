@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBatchSize caps the number of keys in one batch request.
+const maxBatchSize = 100
+
+// TrackShipments looks up several shipments by tracking number in one query.
+// Unknown tracking numbers are reported in NotFound rather than failing the batch.
+func (s *ShippingService) TrackShipments(ctx context.Context, trackingNumbers []string) (*domain.BatchShipmentsResponse, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.track_batch", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("batch.size", len(trackingNumbers)),
+	))
+	defer span.End()
+
+	keys, err := batchKeys(trackingNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.repo.GetByTrackingNumbers(ctx, keys)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(shipments))
+	for _, shipment := range shipments {
+		found[shipment.TrackingNumber] = true
+	}
+
+	resp := &domain.BatchShipmentsResponse{Shipments: shipments, NotFound: missingKeys(keys, found)}
+	span.SetAttributes(attribute.Int("batch.not_found", len(resp.NotFound)))
+	observeLookups("track_batch", len(keys)-len(resp.NotFound), len(resp.NotFound))
+	return resp, nil
+}
+
+// GetShipmentsByOrderIDs looks up the shipment of several orders in one query.
+// Orders without a shipment are reported in NotFound.
+func (s *ShippingService) GetShipmentsByOrderIDs(ctx context.Context, orderIDs []string) (*domain.BatchShipmentsResponse, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.get_by_order_batch", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("batch.size", len(orderIDs)),
+	))
+	defer span.End()
+
+	keys, err := batchKeys(orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(keys))
+	for _, key := range keys {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("get shipments for order %q: %w", key, ErrInvalidBatch)
+		}
+		ids = append(ids, id)
+	}
+
+	shipments, err := s.repo.GetByOrderIDs(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(shipments))
+	for _, shipment := range shipments {
+		found[strconv.Itoa(shipment.OrderID)] = true
+	}
+
+	resp := &domain.BatchShipmentsResponse{Shipments: shipments, NotFound: missingKeys(keys, found)}
+	span.SetAttributes(attribute.Int("batch.not_found", len(resp.NotFound)))
+	observeLookups("get_by_order_batch", len(keys)-len(resp.NotFound), len(resp.NotFound))
+	return resp, nil
+}
+
+// EstimateShippingBatch estimates several parcels; results are in request order.
+func (s *ShippingService) EstimateShippingBatch(
+	ctx context.Context, reqs []domain.EstimateRequest,
+) ([]domain.EstimateResponse, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.estimate_batch", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("batch.size", len(reqs)),
+	))
+	defer span.End()
+
+	if len(reqs) == 0 || len(reqs) > maxBatchSize {
+		return nil, fmt.Errorf("estimate batch of %d parcels: %w", len(reqs), ErrInvalidBatch)
+	}
+
+	estimates := make([]domain.EstimateResponse, 0, len(reqs))
+	for i, req := range reqs {
+		if req.Origin == "" || req.Destination == "" || req.Weight <= 0 {
+			return nil, fmt.Errorf("estimate parcel %d: %w", i, ErrInvalidBatch)
+		}
+		estimate, err := s.EstimateShipping(ctx, req.Origin, req.Destination, req.Weight)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		estimates = append(estimates, *estimate)
+	}
+
+	return estimates, nil
+}
+
+// batchKeys validates the batch size and drops empty and duplicate keys, keeping request order.
+func batchKeys(keys []string) ([]string, error) {
+	if len(keys) == 0 || len(keys) > maxBatchSize {
+		return nil, fmt.Errorf("batch of %d keys: %w", len(keys), ErrInvalidBatch)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("batch with empty key: %w", ErrInvalidBatch)
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique, nil
+}
+
+func missingKeys(keys []string, found map[string]bool) []string {
+	missing := make([]string, 0)
+	for _, key := range keys {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// fakeShipmentRepo serves the batch getters from memory; other methods are not used.
+type fakeShipmentRepo struct {
+	domain.ShipmentRepository
+	shipments []domain.Shipment
+	lookups   [][]string
+}
+
+func (f *fakeShipmentRepo) GetByTrackingNumbers(_ context.Context, trackingNumbers []string) ([]domain.Shipment, error) {
+	f.lookups = append(f.lookups, trackingNumbers)
+	var found []domain.Shipment
+	for _, s := range f.shipments {
+		if slices.Contains(trackingNumbers, s.TrackingNumber) {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeShipmentRepo) GetByOrderIDs(_ context.Context, orderIDs []int) ([]domain.Shipment, error) {
+	var keys []string
+	var found []domain.Shipment
+	for _, id := range orderIDs {
+		keys = append(keys, fmt.Sprint(id))
+	}
+	f.lookups = append(f.lookups, keys)
+	for _, s := range f.shipments {
+		if slices.Contains(orderIDs, s.OrderID) {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func TestTrackShipments(t *testing.T) {
+	repo := &fakeShipmentRepo{shipments: []domain.Shipment{
+		{ID: 1, OrderID: 10, TrackingNumber: "TRK-1"},
+		{ID: 2, OrderID: 20, TrackingNumber: "TRK-2"},
+	}}
+	service := NewShippingService(repo)
+
+	resp, err := service.TrackShipments(context.Background(), []string{"TRK-1", "TRK-9", "TRK-1", "TRK-2"})
+	if err != nil {
+		t.Fatalf("TrackShipments() error = %v", err)
+	}
+	if len(resp.Shipments) != 2 {
+		t.Errorf("len(Shipments) = %d, want 2", len(resp.Shipments))
+	}
+	if !slices.Equal(resp.NotFound, []string{"TRK-9"}) {
+		t.Errorf("NotFound = %v, want [TRK-9]", resp.NotFound)
+	}
+	if got := repo.lookups[0]; !slices.Equal(got, []string{"TRK-1", "TRK-9", "TRK-2"}) {
+		t.Errorf("repository looked up %v, want duplicates removed", got)
+	}
+}
+
+func TestGetShipmentsByOrderIDs(t *testing.T) {
+	repo := &fakeShipmentRepo{shipments: []domain.Shipment{{ID: 1, OrderID: 10, TrackingNumber: "TRK-1"}}}
+	service := NewShippingService(repo)
+
+	resp, err := service.GetShipmentsByOrderIDs(context.Background(), []string{"10", "11"})
+	if err != nil {
+		t.Fatalf("GetShipmentsByOrderIDs() error = %v", err)
+	}
+	if len(resp.Shipments) != 1 || resp.Shipments[0].OrderID != 10 {
+		t.Errorf("Shipments = %+v, want order 10", resp.Shipments)
+	}
+	if !slices.Equal(resp.NotFound, []string{"11"}) {
+		t.Errorf("NotFound = %v, want [11]", resp.NotFound)
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	service := NewShippingService(&fakeShipmentRepo{})
+	ctx := context.Background()
+	tooMany := make([]string, maxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"empty tracking batch", func() error { _, err := service.TrackShipments(ctx, nil); return err }},
+		{"oversized tracking batch", func() error { _, err := service.TrackShipments(ctx, tooMany); return err }},
+		{"empty tracking number", func() error { _, err := service.TrackShipments(ctx, []string{"TRK-1", ""}); return err }},
+		{"non-numeric order ID", func() error { _, err := service.GetShipmentsByOrderIDs(ctx, []string{"abc"}); return err }},
+		{"empty estimate batch", func() error { _, err := service.EstimateShippingBatch(ctx, nil); return err }},
+		{"estimate without weight", func() error {
+			_, err := service.EstimateShippingBatch(ctx, []domain.EstimateRequest{{Origin: "NY", Destination: "CA"}})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrInvalidBatch) {
+				t.Errorf("error = %v, want ErrInvalidBatch", err)
+			}
+		})
+	}
+}
+
+func TestEstimateShippingBatch(t *testing.T) {
+	service := NewShippingService(nil)
+
+	estimates, err := service.EstimateShippingBatch(context.Background(), []domain.EstimateRequest{
+		{Origin: "NY", Destination: "NY", Weight: 2},
+		{Origin: "NY", Destination: "CA", Weight: 12},
+	})
+	if err != nil {
+		t.Fatalf("EstimateShippingBatch() error = %v", err)
+	}
+	if len(estimates) != 2 {
+		t.Fatalf("len(estimates) = %d, want 2", len(estimates))
+	}
+	if estimates[0].EstimatedCost != 8.0 || estimates[1].EstimatedCost != 33.0 {
+		t.Errorf("costs = %v, %v; want 8, 33 in request order", estimates[0].EstimatedCost, estimates[1].EstimatedCost)
+	}
+}
@@ -1,37 +1,118 @@
-// Package v1 provides shipping business logic for API version 1.
-//
-// Error Handling:
-// This package defines sentinel errors for shipping operations.
-// These errors should be wrapped with context using fmt.Errorf("%w").
-//
-// Example Usage:
-//
-//	if shipment == nil {
-//	    return nil, fmt.Errorf("get shipment by id %q: %w", shipmentID, ErrShipmentNotFound)
-//	}
-//
-//	if !isValidAddress(address) {
-//	    return nil, fmt.Errorf("create shipment with address %q: %w", address, ErrInvalidAddress)
-//	}
-package v1
-
-import "errors"
-
-// Sentinel errors for shipping operations.
-var (
-	// ErrShipmentNotFound indicates the requested shipment does not exist.
-	// HTTP Status: 404 Not Found
-	ErrShipmentNotFound = errors.New("shipment not found")
-
-	// ErrInvalidAddress indicates the shipping address is invalid or incomplete.
-	// HTTP Status: 400 Bad Request
-	ErrInvalidAddress = errors.New("invalid address")
-
-	// ErrCarrierUnavailable indicates the shipping carrier is unavailable.
-	// HTTP Status: 503 Service Unavailable
-	ErrCarrierUnavailable = errors.New("carrier unavailable")
-
-	// ErrUnauthorized indicates the user is not authorized to perform the operation.
-	// HTTP Status: 403 Forbidden
-	ErrUnauthorized = errors.New("unauthorized access")
-)
+// Package v1 provides shipping business logic for API version 1.
+//
+// Error Handling:
+// This package defines sentinel errors for shipping operations.
+// These errors should be wrapped with context using fmt.Errorf("%w").
+//
+// Example Usage:
+//
+//	if shipment == nil {
+//	    return nil, fmt.Errorf("get shipment by id %q: %w", shipmentID, ErrShipmentNotFound)
+//	}
+//
+//	if !isValidAddress(address) {
+//	    return nil, fmt.Errorf("create shipment with address %q: %w", address, ErrInvalidAddress)
+//	}
+package v1
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for shipping operations.
+var (
+	// ErrShipmentNotFound indicates the requested shipment does not exist.
+	// HTTP Status: 404 Not Found
+	ErrShipmentNotFound = errors.New("shipment not found")
+
+	// ErrInvalidAddress indicates the shipping address is invalid or incomplete.
+	// HTTP Status: 400 Bad Request
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrCarrierUnavailable indicates the shipping carrier is unavailable.
+	// HTTP Status: 503 Service Unavailable
+	ErrCarrierUnavailable = errors.New("carrier unavailable")
+
+	// ErrUnauthorized indicates the user is not authorized to perform the operation.
+	// HTTP Status: 403 Forbidden
+	ErrUnauthorized = errors.New("unauthorized access")
+
+	// ErrExceptionNotFound indicates the requested delivery exception does not exist.
+	// HTTP Status: 404 Not Found
+	ErrExceptionNotFound = errors.New("shipment exception not found")
+
+	// ErrInvalidExceptionReason indicates an unknown exception reason code.
+	// HTTP Status: 400 Bad Request
+	ErrInvalidExceptionReason = errors.New("invalid exception reason")
+
+	// ErrExceptionConflict indicates the exception state does not allow the operation
+	// (shipment already has an open exception, or the exception is already resolved).
+	// HTTP Status: 409 Conflict
+	ErrExceptionConflict = errors.New("shipment exception conflict")
+
+	// ErrShipmentExists indicates a shipment with the same tracking number already exists.
+	// HTTP Status: 409 Conflict
+	ErrShipmentExists = errors.New("shipment already exists")
+
+	// ErrInvalidShipment indicates the shipment payload is invalid (unknown status,
+	// malformed estimated delivery, ...).
+	// HTTP Status: 400 Bad Request
+	ErrInvalidShipment = errors.New("invalid shipment")
+
+	// ErrInvalidStatusTransition indicates the shipment cannot move to the requested status.
+	// HTTP Status: 409 Conflict
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+	// ErrInvalidSubscription indicates the subscription target is missing or malformed.
+	// HTTP Status: 400 Bad Request
+	ErrInvalidSubscription = errors.New("invalid subscription")
+
+	// ErrSubscriptionNotFound indicates no active subscription matches the unsubscribe token.
+	// HTTP Status: 404 Not Found
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrInvalidWebhook indicates the webhook subscription payload is invalid.
+	// HTTP Status: 400 Bad Request
+	ErrInvalidWebhook = errors.New("invalid webhook subscription")
+
+	// ErrWebhookNotFound indicates the webhook subscription or dead letter does not exist.
+	// HTTP Status: 404 Not Found
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrWebhookInactive indicates a dead letter belongs to a deleted webhook
+	// subscription and cannot be replayed.
+	// HTTP Status: 409 Conflict
+	ErrWebhookInactive = errors.New("webhook subscription inactive")
+
+	// ErrPreconditionFailed indicates the shipment changed since the version
+	// the caller sent in If-Match.
+	// HTTP Status: 412 Precondition Failed
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrInvalidBatch indicates a batch request is empty, too large or has malformed keys.
+	// HTTP Status: 400 Bad Request
+	ErrInvalidBatch = errors.New("invalid batch request")
+)
+
+// FieldError marks a validation failure of one input field. It wraps the
+// sentinel error, so errors.Is still matches, and lets the web layer report
+// the field to the client via errors.As.
+//
+// Example Usage:
+//
+//	return nil, fmt.Errorf("create shipment: %w",
+//	    &FieldError{Field: "estimated_delivery", Message: "must be an RFC3339 timestamp", Err: ErrInvalidShipment})
+type FieldError struct {
+	Field   string // JSON field or parameter name
+	Message string // Client-facing reason
+	Err     error  // Sentinel error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Field, e.Message, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
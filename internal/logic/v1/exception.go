@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultExceptionListLimit caps ListOpenExceptions when the caller passes no limit.
+const defaultExceptionListLimit = 100
+
+// validExceptionReasons lists the reason codes accepted from carriers.
+var validExceptionReasons = map[string]bool{
+	domain.ExceptionAddressNotFound:      true,
+	domain.ExceptionDamaged:              true,
+	domain.ExceptionHeldAtCustoms:        true,
+	domain.ExceptionRecipientUnavailable: true,
+	domain.ExceptionLost:                 true,
+	domain.ExceptionWeatherDelay:         true,
+}
+
+// ExceptionService handles the delivery exception workflow: carriers raise
+// exceptions, ops users list the open ones and resolve them.
+type ExceptionService struct {
+	repo domain.ShipmentExceptionRepository
+}
+
+func NewExceptionService(repo domain.ShipmentExceptionRepository) *ExceptionService {
+	return &ExceptionService{
+		repo: repo,
+	}
+}
+
+// RaiseException opens a delivery exception on a shipment
+func (s *ExceptionService) RaiseException(
+	ctx context.Context, shipmentID int, reasonCode, description string,
+) (*domain.ShipmentException, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.exception.raise", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("shipment.id", shipmentID),
+		attribute.String("exception.reason", reasonCode),
+	))
+	defer span.End()
+
+	if !validExceptionReasons[reasonCode] {
+		return nil, fmt.Errorf("raise exception with reason %q: %w", reasonCode,
+			&FieldError{Field: "reason_code", Message: "is not a known exception reason", Err: ErrInvalidExceptionReason})
+	}
+
+	exception, err := s.repo.Create(ctx, shipmentID, reasonCode, description)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrShipmentNotFound):
+			return nil, ErrShipmentNotFound
+		case errors.Is(err, domain.ErrOpenExceptionExists):
+			return nil, fmt.Errorf("raise exception for shipment %d: %w", shipmentID, ErrExceptionConflict)
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("exception.id", exception.ID))
+	return exception, nil
+}
+
+// ListOpenExceptions returns unresolved exceptions, oldest first
+func (s *ExceptionService) ListOpenExceptions(ctx context.Context, limit int) ([]domain.ShipmentException, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.exception.list_open", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+	))
+	defer span.End()
+
+	if limit <= 0 || limit > defaultExceptionListLimit {
+		limit = defaultExceptionListLimit
+	}
+
+	exceptions, err := s.repo.ListOpen(ctx, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("exception.count", len(exceptions)))
+	return exceptions, nil
+}
+
+// ResolveException closes an open exception; the repository records the
+// resolution as a tracking event on the shipment.
+func (s *ExceptionService) ResolveException(
+	ctx context.Context, exceptionID int, resolution, resolvedBy string,
+) (*domain.ShipmentException, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.exception.resolve", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("exception.id", exceptionID),
+	))
+	defer span.End()
+
+	exception, err := s.repo.Resolve(ctx, exceptionID, resolution, resolvedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrExceptionNotFound):
+			span.SetAttributes(attribute.Bool("exception.found", false))
+			return nil, ErrExceptionNotFound
+		case errors.Is(err, domain.ErrExceptionAlreadyResolved):
+			return nil, fmt.Errorf("resolve exception %d: %w", exceptionID, ErrExceptionConflict)
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("shipment.id", exception.ShipmentID))
+	return exception, nil
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// fakeExceptionRepo is an in-memory domain.ShipmentExceptionRepository.
+type fakeExceptionRepo struct {
+	exceptions map[int]*domain.ShipmentException
+	shipments  map[int]bool
+	nextID     int
+}
+
+func newFakeExceptionRepo(shipmentIDs ...int) *fakeExceptionRepo {
+	repo := &fakeExceptionRepo{exceptions: map[int]*domain.ShipmentException{}, shipments: map[int]bool{}}
+	for _, id := range shipmentIDs {
+		repo.shipments[id] = true
+	}
+	return repo
+}
+
+func (f *fakeExceptionRepo) Create(
+	_ context.Context, shipmentID int, reasonCode, description string,
+) (*domain.ShipmentException, error) {
+	if !f.shipments[shipmentID] {
+		return nil, fmt.Errorf("shipment %d: %w", shipmentID, domain.ErrShipmentNotFound)
+	}
+	for _, e := range f.exceptions {
+		if e.ShipmentID == shipmentID && e.ResolvedAt == nil {
+			return nil, fmt.Errorf("shipment %d: %w", shipmentID, domain.ErrOpenExceptionExists)
+		}
+	}
+	f.nextID++
+	e := &domain.ShipmentException{ID: f.nextID, ShipmentID: shipmentID, ReasonCode: reasonCode, Description: description}
+	f.exceptions[e.ID] = e
+	return e, nil
+}
+
+func (f *fakeExceptionRepo) ListOpen(_ context.Context, limit int) ([]domain.ShipmentException, error) {
+	var open []domain.ShipmentException
+	for id := 1; id <= f.nextID && len(open) < limit; id++ {
+		if e, ok := f.exceptions[id]; ok && e.ResolvedAt == nil {
+			open = append(open, *e)
+		}
+	}
+	return open, nil
+}
+
+func (f *fakeExceptionRepo) Resolve(
+	_ context.Context, exceptionID int, resolution, resolvedBy string,
+) (*domain.ShipmentException, error) {
+	e, ok := f.exceptions[exceptionID]
+	if !ok {
+		return nil, fmt.Errorf("exception %d: %w", exceptionID, domain.ErrExceptionNotFound)
+	}
+	if e.ResolvedAt != nil {
+		return nil, fmt.Errorf("exception %d: %w", exceptionID, domain.ErrExceptionAlreadyResolved)
+	}
+	resolvedAt := "2026-01-01T00:00:00Z"
+	e.ResolvedAt = &resolvedAt
+	e.Resolution = resolution
+	e.ResolvedBy = resolvedBy
+	return e, nil
+}
+
+func TestExceptionWorkflow(t *testing.T) {
+	service := NewExceptionService(newFakeExceptionRepo(1, 2))
+	ctx := context.Background()
+
+	if _, err := service.RaiseException(ctx, 1, "bogus", ""); !errors.Is(err, ErrInvalidExceptionReason) {
+		t.Fatalf("RaiseException() with unknown reason error = %v, want %v", err, ErrInvalidExceptionReason)
+	}
+	if _, err := service.RaiseException(ctx, 99, domain.ExceptionDamaged, ""); !errors.Is(err, ErrShipmentNotFound) {
+		t.Fatalf("RaiseException() on unknown shipment error = %v, want %v", err, ErrShipmentNotFound)
+	}
+
+	exception, err := service.RaiseException(ctx, 1, domain.ExceptionHeldAtCustoms, "awaiting paperwork")
+	if err != nil {
+		t.Fatalf("RaiseException() error = %v", err)
+	}
+	if _, err := service.RaiseException(ctx, 1, domain.ExceptionDamaged, ""); !errors.Is(err, ErrExceptionConflict) {
+		t.Fatalf("second RaiseException() error = %v, want %v", err, ErrExceptionConflict)
+	}
+
+	open, err := service.ListOpenExceptions(ctx, 0)
+	if err != nil || len(open) != 1 {
+		t.Fatalf("ListOpenExceptions() = %d exceptions, err %v; want 1, nil", len(open), err)
+	}
+
+	resolved, err := service.ResolveException(ctx, exception.ID, "released", "ops@example.com")
+	if err != nil {
+		t.Fatalf("ResolveException() error = %v", err)
+	}
+	if resolved.ResolvedAt == nil || resolved.Resolution != "released" {
+		t.Errorf("ResolveException() = %+v, want resolved with resolution %q", resolved, "released")
+	}
+	if _, err := service.ResolveException(ctx, exception.ID, "released", ""); !errors.Is(err, ErrExceptionConflict) {
+		t.Errorf("second ResolveException() error = %v, want %v", err, ErrExceptionConflict)
+	}
+	if _, err := service.ResolveException(ctx, 42, "released", ""); !errors.Is(err, ErrExceptionNotFound) {
+		t.Errorf("ResolveException() on unknown exception error = %v, want %v", err, ErrExceptionNotFound)
+	}
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// serviceLevelStandard labels estimates; EstimateShipping quotes a single
+// service level for now.
+const serviceLevelStandard = "standard"
+
+// carrierLabels maps the carriers the service works with, lowercased, to their
+// metric label. Callers supply the carrier freely, so anything else is "other"
+// to keep the label's cardinality fixed.
+var carrierLabels = map[string]string{
+	"ups":               "ups",
+	"usps":              "usps",
+	"fedex":             "fedex",
+	"dhl":               "dhl",
+	"standard shipping": "standard", // EstimateShipping's quote
+}
+
+var (
+	shipmentsCreated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipments_created_total",
+			Help: "Shipments created",
+		},
+		[]string{"carrier"},
+	)
+
+	statusTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipment_status_transitions_total",
+			Help: "Shipment status changes by the status moved to",
+		},
+		[]string{"carrier", "status"},
+	)
+
+	// Time a shipment spent in from before moving to to, e.g. pending→in_transit
+	// (time to pick-up) and in_transit→delivered (transit time).
+	timeInStatus = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "shipment_time_in_status_seconds",
+			Help:    "Time a shipment spent in a status before a transition",
+			Buckets: []float64{60, 300, 900, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 86400, 2 * 86400, 3 * 86400, 5 * 86400, 7 * 86400, 14 * 86400},
+		},
+		[]string{"carrier", "from", "to"},
+	)
+
+	estimateRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipping_estimates_total",
+			Help: "Shipping estimates quoted",
+		},
+		[]string{"carrier", "service_level"},
+	)
+
+	estimateCost = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "shipping_estimate_cost",
+			Help:    "Estimated shipping cost quoted, in the estimate's currency",
+			Buckets: []float64{5, 10, 15, 20, 30, 50, 75, 100, 200, 500},
+		},
+		[]string{"carrier", "currency"},
+	)
+
+	// Not-found rate: shipment_lookups_total{result="not_found"} / shipment_lookups_total.
+	// Batch lookups count once per requested key.
+	shipmentLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shipment_lookups_total",
+			Help: "Shipment lookups by operation and whether the shipment was found",
+		},
+		[]string{"operation", "result"},
+	)
+)
+
+// carrierLabel maps a carrier to one of carrierLabels, "unknown" or "other".
+func carrierLabel(carrier string) string {
+	carrier = strings.ToLower(strings.TrimSpace(carrier))
+	if carrier == "" {
+		return "unknown"
+	}
+	if label, ok := carrierLabels[carrier]; ok {
+		return label
+	}
+	return "other"
+}
+
+// observeLookups counts found and not-found keys of a shipment lookup.
+func observeLookups(operation string, found, notFound int) {
+	shipmentLookups.WithLabelValues(operation, "found").Add(float64(found))
+	shipmentLookups.WithLabelValues(operation, "not_found").Add(float64(notFound))
+}
+
+// observeStatusUpdate records a transition and, when known, how long the
+// shipment spent in its previous status.
+func observeStatusUpdate(carrier, from, to, since string, now time.Time) {
+	carrier = carrierLabel(carrier)
+	statusTransitions.WithLabelValues(carrier, to).Inc()
+	if entered, err := time.Parse(time.RFC3339, since); err == nil && !entered.After(now) {
+		timeInStatus.WithLabelValues(carrier, from, to).Observe(now.Sub(entered).Seconds())
+	}
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func histogramCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestObserveStatusUpdate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		carrier   string
+		since     string
+		wantTimed bool
+	}{
+		{"timed transition", "UPS", now.Add(-2 * time.Hour).Format(time.RFC3339), true},
+		{"unknown start", "USPS", "", false},
+		{"start in the future", "DHL", now.Add(time.Hour).Format(time.RFC3339), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transitions := statusTransitions.WithLabelValues(carrierLabel(tt.carrier), domain.StatusInTransit)
+			durations := timeInStatus.WithLabelValues(carrierLabel(tt.carrier), domain.StatusPending, domain.StatusInTransit)
+			transitionsBefore, durationsBefore := testutil.ToFloat64(transitions), histogramCount(t, durations)
+
+			observeStatusUpdate(tt.carrier, domain.StatusPending, domain.StatusInTransit, tt.since, now)
+
+			if got := testutil.ToFloat64(transitions) - transitionsBefore; got != 1 {
+				t.Errorf("transitions = %v, want 1", got)
+			}
+			if timed := histogramCount(t, durations) > durationsBefore; timed != tt.wantTimed {
+				t.Errorf("time-in-status observed = %v, want %v", timed, tt.wantTimed)
+			}
+		})
+	}
+}
+
+func TestCarrierLabel(t *testing.T) {
+	tests := []struct {
+		carrier string
+		want    string
+	}{
+		{"", "unknown"},
+		{" UPS ", "ups"},
+		{"FedEx", "fedex"},
+		{"Standard Shipping", "standard"},
+		{"acme-courier-7f3a", "other"},
+		{strings.Repeat("x", 64), "other"},
+	}
+	for _, tt := range tests {
+		if got := carrierLabel(tt.carrier); got != tt.want {
+			t.Errorf("carrierLabel(%q) = %q, want %q", tt.carrier, got, tt.want)
+		}
+	}
+}
+
+func TestBatchLookupsCountNotFoundKeys(t *testing.T) {
+	found := shipmentLookups.WithLabelValues("track_batch", "found")
+	notFound := shipmentLookups.WithLabelValues("track_batch", "not_found")
+	foundBefore, notFoundBefore := testutil.ToFloat64(found), testutil.ToFloat64(notFound)
+
+	repo := &fakeShipmentRepo{shipments: []domain.Shipment{{ID: 1, TrackingNumber: "TRK-1"}}}
+	if _, err := NewShippingService(repo).TrackShipments(context.Background(), []string{"TRK-1", "TRK-8", "TRK-9"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(found) - foundBefore; got != 1 {
+		t.Errorf("found lookups = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(notFound) - notFoundBefore; got != 2 {
+		t.Errorf("not-found lookups = %v, want 2", got)
+	}
+}
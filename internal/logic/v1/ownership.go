@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// OwnershipProof is what a public tracking caller offers to show a shipment is theirs.
+type OwnershipProof struct {
+	PostalCode string // Destination postal code of the shipment
+	Token      string // Customer token issued for the shipment's order
+}
+
+// Empty reports whether the caller offered no proof at all.
+func (p OwnershipProof) Empty() bool {
+	return p.PostalCode == "" && p.Token == ""
+}
+
+// OwnershipVerifier decides how much of a shipment a public tracking caller
+// may see, and issues the customer tokens that prove ownership of an order.
+//
+// Tokens have the form "<expiry unix>.<base64url HMAC-SHA256>" over
+// "<order id>.<expiry unix>", so they are bound to one order and need no storage.
+type OwnershipVerifier struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewOwnershipVerifier creates a verifier signing customer tokens with secret.
+func NewOwnershipVerifier(secret string, tokenTTL time.Duration) *OwnershipVerifier {
+	return &OwnershipVerifier{secret: []byte(secret), ttl: tokenTTL, now: time.Now}
+}
+
+// Authorize reports whether proof allows full shipment details. Without any
+// proof the caller gets the status-only view (false, nil); a proof that does
+// not match returns ErrUnauthorized. A nil verifier (verification disabled)
+// allows full details.
+func (v *OwnershipVerifier) Authorize(shipment *domain.Shipment, proof OwnershipProof) (bool, error) {
+	if v == nil {
+		return true, nil
+	}
+	if proof.Empty() {
+		return false, nil
+	}
+
+	if proof.PostalCode != "" && shipment.DestinationPostalCode != "" &&
+		subtle.ConstantTimeCompare(
+			[]byte(normalizePostalCode(proof.PostalCode)),
+			[]byte(normalizePostalCode(shipment.DestinationPostalCode)),
+		) == 1 {
+		return true, nil
+	}
+	if proof.Token != "" && v.validToken(proof.Token, shipment.OrderID) {
+		return true, nil
+	}
+	return false, fmt.Errorf("track shipment %q: ownership proof does not match: %w", shipment.TrackingNumber, ErrUnauthorized)
+}
+
+// IssueToken returns a customer token for an order, valid for the configured TTL.
+func (v *OwnershipVerifier) IssueToken(orderID int) domain.CustomerToken {
+	expires := v.now().Add(v.ttl).UTC().Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return domain.CustomerToken{
+		Token:     exp + "." + base64.RawURLEncoding.EncodeToString(v.mac(orderID, exp)),
+		ExpiresAt: expires.Format(time.RFC3339),
+	}
+}
+
+func (v *OwnershipVerifier) validToken(token string, orderID int) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || v.now().Unix() >= expUnix {
+		return false
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, v.mac(orderID, exp))
+}
+
+func (v *OwnershipVerifier) mac(orderID int, exp string) []byte {
+	m := hmac.New(sha256.New, v.secret)
+	m.Write([]byte(strconv.Itoa(orderID)))
+	m.Write([]byte("."))
+	m.Write([]byte(exp))
+	return m.Sum(nil)
+}
+
+// normalizePostalCode ignores case, spaces and dashes ("sw1a 1aa" == "SW1A1AA").
+func normalizePostalCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// StatusOnly returns the view of a shipment for callers without ownership proof.
+func StatusOnly(shipment *domain.Shipment) domain.ShipmentStatus {
+	return domain.ShipmentStatus{TrackingNumber: shipment.TrackingNumber, Status: shipment.Status}
+}
+
+// StatusOnlyEvent returns the view of a tracking event for callers without
+// ownership proof. Exception events change no status and are not shown (false).
+func StatusOnlyEvent(event domain.TrackingEvent) (domain.StatusEvent, bool) {
+	if event.EventType == domain.EventExceptionOpened || event.EventType == domain.EventExceptionResolved {
+		return domain.StatusEvent{}, false
+	}
+	return domain.StatusEvent{TrackingNumber: event.TrackingNumber, Status: event.Status, OccurredAt: event.OccurredAt}, true
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+func TestOwnershipVerifierAuthorize(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	v := NewOwnershipVerifier("0123456789abcdef0123456789abcdef", time.Hour)
+	v.now = func() time.Time { return now }
+
+	shipment := &domain.Shipment{OrderID: 42, TrackingNumber: "TRK42", Status: domain.StatusInTransit, DestinationPostalCode: "SW1A 1AA"}
+	noPostal := &domain.Shipment{OrderID: 42, TrackingNumber: "TRK42", Status: domain.StatusInTransit}
+
+	valid := v.IssueToken(42).Token
+	otherOrder := v.IssueToken(43).Token
+	v.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	expired := v.IssueToken(42).Token
+	v.now = func() time.Time { return now }
+	otherSecret := NewOwnershipVerifier("another-secret-another-secret-xx", time.Hour).IssueToken(42).Token
+
+	tests := []struct {
+		name     string
+		verifier *OwnershipVerifier
+		shipment *domain.Shipment
+		proof    OwnershipProof
+		wantFull bool
+		wantErr  bool
+	}{
+		{"verification disabled", nil, shipment, OwnershipProof{}, true, false},
+		{"no proof", v, shipment, OwnershipProof{}, false, false},
+		{"postal code", v, shipment, OwnershipProof{PostalCode: "SW1A 1AA"}, true, false},
+		{"postal code normalized", v, shipment, OwnershipProof{PostalCode: " sw1a-1aa "}, true, false},
+		{"wrong postal code", v, shipment, OwnershipProof{PostalCode: "EC1A 1BB"}, false, true},
+		{"no postal code on file", v, noPostal, OwnershipProof{PostalCode: ""}, false, false},
+		{"postal code without one on file", v, noPostal, OwnershipProof{PostalCode: "SW1A 1AA"}, false, true},
+		{"token", v, noPostal, OwnershipProof{Token: valid}, true, false},
+		{"token for another order", v, shipment, OwnershipProof{Token: otherOrder}, false, true},
+		{"expired token", v, shipment, OwnershipProof{Token: expired}, false, true},
+		{"token from another secret", v, shipment, OwnershipProof{Token: otherSecret}, false, true},
+		{"malformed token", v, shipment, OwnershipProof{Token: "garbage"}, false, true},
+		{"wrong postal code, valid token", v, shipment, OwnershipProof{PostalCode: "EC1A 1BB", Token: valid}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full, err := tt.verifier.Authorize(tt.shipment, tt.proof)
+			if full != tt.wantFull {
+				t.Errorf("full = %v, want %v", full, tt.wantFull)
+			}
+			if tt.wantErr != errors.Is(err, ErrUnauthorized) {
+				t.Errorf("err = %v, want ErrUnauthorized: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssueTokenExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+	v := NewOwnershipVerifier("0123456789abcdef0123456789abcdef", 24*time.Hour)
+	v.now = func() time.Time { return now }
+
+	token := v.IssueToken(7)
+	if token.ExpiresAt != "2026-03-02T12:00:00Z" {
+		t.Errorf("ExpiresAt = %q, want 2026-03-02T12:00:00Z", token.ExpiresAt)
+	}
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/duynhne/shipping-service/internal/core/domain"
 	"github.com/duynhne/shipping-service/middleware"
@@ -32,11 +34,13 @@ func (s *ShippingService) TrackShipment(ctx context.Context, trackingNumber stri
 	if err != nil {
 		if errors.Is(err, domain.ErrShipmentNotFound) {
 			span.SetAttributes(attribute.Bool("shipment.found", false))
+			observeLookups("track", 0, 1)
 			return nil, ErrShipmentNotFound
 		}
 		span.RecordError(err)
 		return nil, err
 	}
+	observeLookups("track", 1, 0)
 
 	span.SetAttributes(
 		attribute.Bool("shipment.found", true),
@@ -93,6 +97,9 @@ func (s *ShippingService) EstimateShipping(ctx context.Context, origin, destinat
 		attribute.Float64("estimate.cost", totalCost),
 		attribute.Int("estimate.days", estimatedDays),
 	)
+	carrier := carrierLabel(response.Carrier)
+	estimateRequests.WithLabelValues(carrier, serviceLevelStandard).Inc()
+	estimateCost.WithLabelValues(carrier, response.Currency).Observe(totalCost)
 
 	return response, nil
 }
@@ -110,11 +117,13 @@ func (s *ShippingService) GetShipmentByOrderID(ctx context.Context, orderID stri
 	if err != nil {
 		if errors.Is(err, domain.ErrShipmentNotFound) {
 			span.SetAttributes(attribute.Bool("shipment.found", false))
+			observeLookups("get_by_order", 0, 1)
 			return nil, ErrShipmentNotFound
 		}
 		span.RecordError(err)
 		return nil, err
 	}
+	observeLookups("get_by_order", 1, 0)
 
 	span.SetAttributes(
 		attribute.Bool("shipment.found", true),
@@ -124,3 +133,74 @@ func (s *ShippingService) GetShipmentByOrderID(ctx context.Context, orderID stri
 
 	return shipment, nil
 }
+
+// CreateShipment registers a new pending shipment for an order
+func (s *ShippingService) CreateShipment(ctx context.Context, req *domain.CreateShipmentRequest) (*domain.Shipment, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.create", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("order_id", req.OrderID),
+		attribute.String("tracking.number", req.TrackingNumber),
+	))
+	defer span.End()
+
+	if req.EstimatedDelivery != nil {
+		if _, err := time.Parse(time.RFC3339, *req.EstimatedDelivery); err != nil {
+			return nil, fmt.Errorf("create shipment with estimated delivery %q: %w", *req.EstimatedDelivery,
+				&FieldError{Field: "estimated_delivery", Message: "must be an RFC3339 timestamp", Err: ErrInvalidShipment})
+		}
+	}
+
+	shipment, err := s.repo.Create(ctx, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrDuplicateTrackingNumber) {
+			return nil, fmt.Errorf("create shipment %q: %w", req.TrackingNumber, ErrShipmentExists)
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	shipmentsCreated.WithLabelValues(carrierLabel(shipment.Carrier)).Inc()
+	span.SetAttributes(attribute.Int("shipment.id", shipment.ID))
+	return shipment, nil
+}
+
+// UpdateShipmentStatus moves a shipment to a new status. The repository writes
+// the tracking event and the outbox row in the same transaction. A non-nil
+// ifMatch lists the versions the caller last saw; if the shipment has moved on,
+// ErrPreconditionFailed is returned and nothing is written.
+func (s *ShippingService) UpdateShipmentStatus(
+	ctx context.Context, shipmentID int, status, description string, ifMatch []int,
+) (*domain.Shipment, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.update_status", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("shipment.id", shipmentID),
+		attribute.String("shipment.status", status),
+	))
+	defer span.End()
+
+	if !domain.IsValidStatus(status) {
+		return nil, fmt.Errorf("update shipment %d to status %q: %w", shipmentID, status,
+			&FieldError{Field: "status", Message: "is not a known shipment status", Err: ErrInvalidShipment})
+	}
+
+	update, err := s.repo.UpdateStatus(ctx, shipmentID, status, description, ifMatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrShipmentNotFound):
+			span.SetAttributes(attribute.Bool("shipment.found", false))
+			return nil, ErrShipmentNotFound
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			return nil, fmt.Errorf("update shipment %d to status %q: %w", shipmentID, status, ErrInvalidStatusTransition)
+		case errors.Is(err, domain.ErrVersionMismatch):
+			span.SetAttributes(attribute.Bool("shipment.stale", true))
+			return nil, fmt.Errorf("update shipment %d to status %q: %w", shipmentID, status, ErrPreconditionFailed)
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	observeStatusUpdate(update.Shipment.Carrier, update.PreviousStatus, status, update.PreviousSince, time.Now())
+	return update.Shipment, nil
+}
@@ -2,7 +2,12 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
 )
 
 func TestEstimateShipping(t *testing.T) {
@@ -78,3 +83,148 @@ func TestEstimateShipping(t *testing.T) {
 		})
 	}
 }
+
+// outboxShipmentRepo mimics the transactional contract of
+// postgres.ShipmentRepository: a successful Create or UpdateStatus commits
+// exactly one outbox row, a failed one commits nothing.
+type outboxShipmentRepo struct {
+	domain.ShipmentRepository
+	shipments map[int]*domain.Shipment
+	outbox    []domain.ShipmentEventPayload
+	eventType []string
+}
+
+func newOutboxShipmentRepo(shipments ...domain.Shipment) *outboxShipmentRepo {
+	repo := &outboxShipmentRepo{shipments: map[int]*domain.Shipment{}}
+	for _, s := range shipments {
+		repo.shipments[s.ID] = &s
+	}
+	return repo
+}
+
+func (f *outboxShipmentRepo) writeOutbox(eventType string, s *domain.Shipment, previousStatus, description string) {
+	f.eventType = append(f.eventType, eventType)
+	f.outbox = append(f.outbox, domain.ShipmentEventPayload{
+		ShipmentID: s.ID, OrderID: s.OrderID, TrackingNumber: s.TrackingNumber, Carrier: s.Carrier,
+		PreviousStatus: previousStatus, Status: s.Status, Description: description,
+	})
+}
+
+func (f *outboxShipmentRepo) Create(_ context.Context, req *domain.CreateShipmentRequest) (*domain.Shipment, error) {
+	for _, s := range f.shipments {
+		if s.TrackingNumber == req.TrackingNumber {
+			return nil, fmt.Errorf("create shipment %q: %w", req.TrackingNumber, domain.ErrDuplicateTrackingNumber)
+		}
+	}
+	s := &domain.Shipment{
+		ID: len(f.shipments) + 1, OrderID: req.OrderID, TrackingNumber: req.TrackingNumber,
+		Carrier: req.Carrier, Status: domain.StatusPending, Version: 1,
+	}
+	f.shipments[s.ID] = s
+	f.writeOutbox(domain.OutboxShipmentCreated, s, "", "")
+	return s, nil
+}
+
+func (f *outboxShipmentRepo) UpdateStatus(
+	_ context.Context, shipmentID int, status, description string, ifVersion []int,
+) (*domain.StatusUpdate, error) {
+	s, ok := f.shipments[shipmentID]
+	if !ok {
+		return nil, fmt.Errorf("get shipment %d: %w", shipmentID, domain.ErrShipmentNotFound)
+	}
+	if ifVersion != nil && !slices.Contains(ifVersion, s.Version) {
+		return nil, fmt.Errorf("update shipment %d: %w", shipmentID, domain.ErrVersionMismatch)
+	}
+	if !domain.CanTransition(s.Status, status) {
+		return nil, fmt.Errorf("update shipment %d: %w", shipmentID, domain.ErrInvalidStatusTransition)
+	}
+	previous := s.Status
+	s.Status = status
+	s.Version++
+	f.writeOutbox(domain.OutboxShipmentStatusChanged, s, previous, description)
+	return &domain.StatusUpdate{Shipment: s, PreviousStatus: previous}, nil
+}
+
+func TestCreateShipmentWritesOutboxEvent(t *testing.T) {
+	notRFC3339 := "tomorrow"
+	tests := []struct {
+		name       string
+		req        domain.CreateShipmentRequest
+		wantErr    error
+		wantEvents int
+	}{
+		{"created", domain.CreateShipmentRequest{OrderID: 7, TrackingNumber: "TRK-NEW", Carrier: "UPS"}, nil, 1},
+		{"duplicate", domain.CreateShipmentRequest{OrderID: 7, TrackingNumber: "TRK-1"}, ErrShipmentExists, 0},
+		{
+			"invalid estimated delivery",
+			domain.CreateShipmentRequest{OrderID: 7, TrackingNumber: "TRK-NEW", EstimatedDelivery: &notRFC3339},
+			ErrInvalidShipment, 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newOutboxShipmentRepo(domain.Shipment{ID: 1, TrackingNumber: "TRK-1", Status: domain.StatusPending})
+
+			_, err := NewShippingService(repo).CreateShipment(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateShipment() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.outbox) != tt.wantEvents {
+				t.Fatalf("outbox rows = %d, want %d", len(repo.outbox), tt.wantEvents)
+			}
+			if tt.wantEvents == 0 {
+				return
+			}
+			got := repo.outbox[0]
+			if repo.eventType[0] != domain.OutboxShipmentCreated || got.Status != domain.StatusPending ||
+				got.OrderID != tt.req.OrderID || got.TrackingNumber != tt.req.TrackingNumber || got.Carrier != tt.req.Carrier {
+				t.Errorf("outbox row = %s %+v", repo.eventType[0], got)
+			}
+		})
+	}
+}
+
+func TestUpdateShipmentStatusWritesOutboxEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		shipmentID int
+		status     string
+		ifMatch    []int
+		wantErr    error
+		wantEvents int
+	}{
+		{"status changed", 1, domain.StatusInTransit, nil, nil, 1},
+		{"matching version", 1, domain.StatusInTransit, []int{3}, nil, 1},
+		{"stale version", 1, domain.StatusInTransit, []int{2}, ErrPreconditionFailed, 0},
+		{"invalid transition", 1, domain.StatusPending, nil, ErrInvalidStatusTransition, 0},
+		{"unknown status", 1, "teleported", nil, ErrInvalidShipment, 0},
+		{"not found", 9, domain.StatusInTransit, nil, ErrShipmentNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newOutboxShipmentRepo(domain.Shipment{
+				ID: 1, OrderID: 7, TrackingNumber: "TRK-1", Carrier: "UPS", Status: domain.StatusPending, Version: 3,
+			})
+
+			_, err := NewShippingService(repo).UpdateShipmentStatus(
+				context.Background(), tt.shipmentID, tt.status, "Picked up", tt.ifMatch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateShipmentStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.outbox) != tt.wantEvents {
+				t.Fatalf("outbox rows = %d, want %d", len(repo.outbox), tt.wantEvents)
+			}
+			if tt.wantEvents == 0 {
+				return
+			}
+			want := domain.ShipmentEventPayload{
+				ShipmentID: 1, OrderID: 7, TrackingNumber: "TRK-1", Carrier: "UPS",
+				PreviousStatus: domain.StatusPending, Status: tt.status, Description: "Picked up",
+			}
+			if repo.eventType[0] != domain.OutboxShipmentStatusChanged || repo.outbox[0] != want {
+				t.Errorf("outbox row = %s %+v, want %s %+v",
+					repo.eventType[0], repo.outbox[0], domain.OutboxShipmentStatusChanged, want)
+			}
+		})
+	}
+}
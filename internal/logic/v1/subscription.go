@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/netguard"
+	"github.com/duynhne/shipping-service/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SubscriptionService manages customer subscriptions to tracking updates.
+// Notifications themselves are queued by notify.Dispatcher from outbox events
+// and sent by notify.Worker.
+type SubscriptionService struct {
+	repo domain.SubscriptionRepository
+}
+
+func NewSubscriptionService(repo domain.SubscriptionRepository) *SubscriptionService {
+	return &SubscriptionService{
+		repo: repo,
+	}
+}
+
+// Subscribe registers an email address or webhook URL for a shipment's updates.
+// created is false when the target was already subscribed; the existing
+// subscription is then returned without its unsubscribe token.
+func (s *SubscriptionService) Subscribe(
+	ctx context.Context, req *domain.SubscribeRequest,
+) (subscription *domain.TrackingSubscription, created bool, err error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.subscription.create", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.String("tracking.number", req.TrackingNumber),
+	))
+	defer span.End()
+
+	channel, target, err := subscriptionTarget(req)
+	if err != nil {
+		return nil, false, err
+	}
+	span.SetAttributes(attribute.String("subscription.channel", channel))
+	if channel == domain.ChannelWebhook {
+		if err := checkWebhookHost(ctx, target); err != nil {
+			return nil, false, err
+		}
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		span.RecordError(err)
+		return nil, false, err
+	}
+
+	subscription, created, err = s.repo.Create(ctx, req.TrackingNumber, channel, target, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrShipmentNotFound) {
+			span.SetAttributes(attribute.Bool("shipment.found", false))
+			return nil, false, ErrShipmentNotFound
+		}
+		span.RecordError(err)
+		return nil, false, err
+	}
+
+	span.SetAttributes(attribute.Int("subscription.id", subscription.ID), attribute.Bool("subscription.created", created))
+	return subscription, created, nil
+}
+
+// Unsubscribe deactivates the subscription identified by its unsubscribe token
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) error {
+	ctx, span := middleware.StartSpan(ctx, "shipping.subscription.delete", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+	))
+	defer span.End()
+
+	if token == "" {
+		return fmt.Errorf("unsubscribe: %w", &FieldError{Field: "token", Message: "is required", Err: ErrInvalidSubscription})
+	}
+
+	if err := s.repo.Unsubscribe(ctx, token); err != nil {
+		if errors.Is(err, domain.ErrSubscriptionNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// subscriptionTarget validates that exactly one of email or webhook URL is set.
+func subscriptionTarget(req *domain.SubscribeRequest) (channel, target string, err error) {
+	switch {
+	case req.Email != "" && req.WebhookURL != "":
+		return "", "", fmt.Errorf("subscribe with both email and webhook_url: %w",
+			&FieldError{Field: "webhook_url", Message: "must not be set together with email", Err: ErrInvalidSubscription})
+	case req.Email != "":
+		addr, err := mail.ParseAddress(req.Email)
+		if err != nil || addr.Name != "" {
+			return "", "", fmt.Errorf("subscribe with email %q: %w", req.Email,
+				&FieldError{Field: "email", Message: "is not a valid email address", Err: ErrInvalidSubscription})
+		}
+		return domain.ChannelEmail, addr.Address, nil
+	case req.WebhookURL != "":
+		u, err := url.Parse(req.WebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "", "", fmt.Errorf("subscribe with webhook_url %q: %w", req.WebhookURL,
+				&FieldError{Field: "webhook_url", Message: "must be an absolute http(s) URL", Err: ErrInvalidSubscription})
+		}
+		return domain.ChannelWebhook, u.String(), nil
+	default:
+		return "", "", fmt.Errorf("subscribe: %w",
+			&FieldError{Field: "email", Message: "is required when webhook_url is not set", Err: ErrInvalidSubscription})
+	}
+}
+
+// checkWebhookHost rejects webhook URLs whose host resolves to a loopback,
+// private, link-local or otherwise internal address. The notifier re-checks
+// every address it dials, in case the name is re-pointed later.
+func checkWebhookHost(ctx context.Context, target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("subscribe with webhook_url %q: %w", target,
+			&FieldError{Field: "webhook_url", Message: "must be an absolute http(s) URL", Err: ErrInvalidSubscription})
+	}
+	if err := netguard.CheckHost(ctx, u.Hostname()); err != nil {
+		message := "host could not be resolved"
+		if errors.Is(err, netguard.ErrForbiddenAddress) {
+			message = "must point to a public address"
+		}
+		return fmt.Errorf("subscribe with webhook_url %q: %v: %w", target, err,
+			&FieldError{Field: "webhook_url", Message: message, Err: ErrInvalidSubscription})
+	}
+	return nil
+}
+
+// newRandomToken returns 32 random bytes, hex encoded. Used for unsubscribe
+// tokens and generated webhook secrets.
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
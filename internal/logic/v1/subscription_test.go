@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+func TestSubscriptionTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         domain.SubscribeRequest
+		wantChannel string
+		wantTarget  string
+		wantErr     bool
+	}{
+		{
+			name:        "Email",
+			req:         domain.SubscribeRequest{Email: "alice@example.com"},
+			wantChannel: domain.ChannelEmail,
+			wantTarget:  "alice@example.com",
+		},
+		{
+			name:        "Webhook",
+			req:         domain.SubscribeRequest{WebhookURL: "https://hooks.example.com/track"},
+			wantChannel: domain.ChannelWebhook,
+			wantTarget:  "https://hooks.example.com/track",
+		},
+		{name: "Neither", req: domain.SubscribeRequest{}, wantErr: true},
+		{name: "Both", req: domain.SubscribeRequest{Email: "a@example.com", WebhookURL: "https://x.io"}, wantErr: true},
+		{name: "Invalid email", req: domain.SubscribeRequest{Email: "not-an-email"}, wantErr: true},
+		{name: "Display name header injection", req: domain.SubscribeRequest{Email: "Eve <eve@example.com>"}, wantErr: true},
+		{name: "Non-HTTP webhook", req: domain.SubscribeRequest{WebhookURL: "ftp://hooks.example.com"}, wantErr: true},
+		{name: "Relative webhook", req: domain.SubscribeRequest{WebhookURL: "/hooks"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, target, err := subscriptionTarget(&tt.req)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSubscription) {
+					t.Errorf("subscriptionTarget() error = %v, want %v", err, ErrInvalidSubscription)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("subscriptionTarget() error = %v", err)
+			}
+			if channel != tt.wantChannel || target != tt.wantTarget {
+				t.Errorf("subscriptionTarget() = %s/%s, want %s/%s", channel, target, tt.wantChannel, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestCheckWebhookHostRejectsInternalAddresses(t *testing.T) {
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"http://[::1]/hook",
+		"http://[fd00:ec2::254]/hook",
+	} {
+		err := checkWebhookHost(context.Background(), target)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != "webhook_url" || !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("checkWebhookHost(%q) = %v, want a webhook_url field error", target, err)
+		}
+	}
+	if err := checkWebhookHost(context.Background(), "https://93.184.215.14/hook"); err != nil {
+		t.Errorf("checkWebhookHost(public IP) = %v, want nil", err)
+	}
+}
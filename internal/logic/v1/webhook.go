@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// minWebhookSecretLength guards against trivially guessable partner-supplied secrets.
+const minWebhookSecretLength = 16
+
+// defaultDeadLetterListLimit caps ListDeadLetters.
+const defaultDeadLetterListLimit = 100
+
+// webhookEventTypes lists the event types a subscription may filter on.
+var webhookEventTypes = map[string]bool{
+	domain.OutboxShipmentCreated:       true,
+	domain.OutboxShipmentStatusChanged: true,
+}
+
+// WebhookService manages the merchant webhook registry and dead-letter replay.
+// Deliveries are queued by webhook.Dispatcher and sent by webhook.Worker.
+type WebhookService struct {
+	repo domain.WebhookRepository
+}
+
+func NewWebhookService(repo domain.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+	}
+}
+
+// CreateSubscription registers a merchant endpoint. A signing secret is
+// generated when none is supplied; it is only returned in this response.
+func (s *WebhookService) CreateSubscription(
+	ctx context.Context, req *domain.CreateWebhookSubscriptionRequest,
+) (*domain.WebhookSubscription, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.webhook.create", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+	))
+	defer span.End()
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("create webhook with url %q: %w", req.URL,
+			&FieldError{Field: "url", Message: "must be an absolute http(s) URL", Err: ErrInvalidWebhook})
+	}
+	for _, eventType := range req.EventTypes {
+		if !webhookEventTypes[eventType] {
+			return nil, fmt.Errorf("create webhook with event type %q: %w", eventType,
+				&FieldError{Field: "event_types", Message: "contains an unknown event type", Err: ErrInvalidWebhook})
+		}
+	}
+
+	secret := req.Secret
+	switch {
+	case secret == "":
+		if secret, err = newRandomToken(); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	case len(secret) < minWebhookSecretLength:
+		return nil, fmt.Errorf("create webhook with %d-char secret: %w", len(secret),
+			&FieldError{Field: "secret", Message: "must be at least 16 characters", Err: ErrInvalidWebhook})
+	}
+
+	subscription, err := s.repo.CreateSubscription(ctx, &domain.WebhookSubscription{
+		URL:        u.String(),
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Carrier:    req.Carrier,
+		OrderID:    req.OrderID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("webhook.subscription_id", subscription.ID))
+	return subscription, nil
+}
+
+// ListSubscriptions returns active subscriptions (secrets omitted)
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.webhook.list", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+	))
+	defer span.End()
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription deactivates a subscription; queued deliveries are no longer sent
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int) error {
+	ctx, span := middleware.StartSpan(ctx, "shipping.webhook.delete", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int("webhook.subscription_id", id),
+	))
+	defer span.End()
+
+	if err := s.repo.DeactivateSubscription(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrWebhookSubscriptionNotFound) {
+			return ErrWebhookNotFound
+		}
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// ListDeadLetters returns deliveries that exhausted their retries and were not replayed
+func (s *WebhookService) ListDeadLetters(ctx context.Context, limit int) ([]domain.WebhookDeadLetter, error) {
+	ctx, span := middleware.StartSpan(ctx, "shipping.webhook.list_dead_letters", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+	))
+	defer span.End()
+
+	if limit <= 0 || limit > defaultDeadLetterListLimit {
+		limit = defaultDeadLetterListLimit
+	}
+
+	deadLetters, err := s.repo.ListDeadLetters(ctx, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+// ReplayDeadLetter re-queues a dead-lettered delivery with a fresh retry budget.
+// Dead letters of deleted subscriptions return ErrWebhookInactive, since the
+// worker would never send them.
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, id int64) error {
+	ctx, span := middleware.StartSpan(ctx, "shipping.webhook.replay", trace.WithAttributes(
+		attribute.String("layer", "logic"),
+		attribute.String("api.version", "v1"),
+		attribute.Int64("webhook.dead_letter_id", id),
+	))
+	defer span.End()
+
+	if err := s.repo.ReplayDeadLetter(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrDeadLetterNotFound) {
+			return ErrWebhookNotFound
+		}
+		if errors.Is(err, domain.ErrWebhookSubscriptionInactive) {
+			return fmt.Errorf("replay dead letter %d: %w", id, ErrWebhookInactive)
+		}
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
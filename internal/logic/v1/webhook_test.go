@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// replayRepo returns err from ReplayDeadLetter.
+type replayRepo struct {
+	domain.WebhookRepository
+	err error
+}
+
+func (r replayRepo) ReplayDeadLetter(context.Context, int64) error { return r.err }
+
+func TestReplayDeadLetter(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		want    error
+	}{
+		{"requeued", nil, nil},
+		{"unknown or already replayed", fmt.Errorf("replay: %w", domain.ErrDeadLetterNotFound), ErrWebhookNotFound},
+		{"subscription deleted", fmt.Errorf("replay: %w", domain.ErrWebhookSubscriptionInactive), ErrWebhookInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewWebhookService(replayRepo{err: tt.repoErr}).ReplayDeadLetter(context.Background(), 1)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("ReplayDeadLetter() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package netguard keeps outbound requests to caller-supplied URLs away from
+// the service's own network (SSRF protection).
+//
+// CheckHost validates a host when the URL is registered, so callers get an
+// immediate error. Control re-checks every address actually dialed: a name can
+// resolve to a public address at registration and to an internal one later
+// (DNS rebinding), so the registration check alone is not enough.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for hosts that resolve to a non-public address.
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// blockedPrefixes are special-purpose ranges not covered by the netip
+// predicates used in Allowed.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT, also some cloud metadata services
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64: embeds an IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4: embeds an IPv4 address
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// Allowed reports whether ip is a public unicast address. Loopback, private,
+// link-local (including the 169.254.169.254 metadata endpoint), multicast and
+// other special-purpose addresses are rejected.
+func Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves host and returns ErrForbiddenAddress if any of its
+// addresses is not Allowed.
+func CheckHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !Allowed(ip) {
+			return fmt.Errorf("host %s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve host %s: %w", host, err)
+	}
+	for _, ip := range addrs {
+		if !Allowed(ip) {
+			return fmt.Errorf("host %s resolves to %s: %w", host, ip, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control function that refuses connections to
+// addresses that are not Allowed. It runs for every address dialed, after
+// name resolution.
+func Control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", address, err)
+	}
+	if !Allowed(addrPort.Addr()) {
+		return fmt.Errorf("dial %s: %w", address, ErrForbiddenAddress)
+	}
+	return nil
+}
+
+// NewClient returns an HTTP client that only connects to Allowed addresses,
+// including on redirects. Proxy settings from the environment are ignored:
+// with a proxy, only the proxy's address would be checked.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: Control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+var (
+	subjectTemplate = template.Must(template.New("subject").Parse(
+		`Your shipment {{.Event.TrackingNumber}} is now {{.StatusLabel}}`,
+	))
+
+	bodyTemplate = template.Must(template.New("body").Parse(`Hello,
+
+Your shipment {{.Event.TrackingNumber}}{{with .Event.Carrier}} ({{.}}){{end}} is now {{.StatusLabel}}.
+{{- with .Event.Description}}
+
+{{.}}
+{{- end}}
+
+Track it at: {{.TrackURL}}
+
+To stop receiving these updates: {{.UnsubscribeURL}}
+`))
+)
+
+// statusLabels are the customer-facing names of shipment statuses.
+var statusLabels = map[string]string{
+	domain.StatusPending:        "awaiting pickup",
+	domain.StatusInTransit:      "in transit",
+	domain.StatusOutForDelivery: "out for delivery",
+	domain.StatusDelivered:      "delivered",
+	domain.StatusReturned:       "returned to sender",
+	domain.StatusCancelled:      "cancelled",
+}
+
+// templateData is the data available to the message templates.
+type templateData struct {
+	Event          domain.ShipmentEventPayload
+	StatusLabel    string
+	TrackURL       string
+	UnsubscribeURL string
+}
+
+// Dispatcher queues a notification per active subscription for each
+// shipment status event. It implements outbox.Publisher; the Worker sends the
+// queued notifications.
+type Dispatcher struct {
+	repo domain.SubscriptionRepository
+}
+
+func NewDispatcher(repo domain.SubscriptionRepository) *Dispatcher {
+	return &Dispatcher{repo: repo}
+}
+
+// Publish queues notifications of a status transition. Other event types are
+// ignored. Only a failure to queue is returned: subscribers that cannot be
+// reached are retried by the Worker, without failing the outbox event.
+func (d *Dispatcher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	if event.EventType != domain.OutboxShipmentStatusChanged {
+		return nil
+	}
+	return d.repo.EnqueueNotifications(ctx, event)
+}
+
+// render builds the message of a status event for one subscription.
+// publicBaseURL is the externally reachable base of this service
+// (e.g. https://shop.example.com) used for links in messages.
+func render(
+	publicBaseURL string, payload domain.ShipmentEventPayload, subscription domain.TrackingSubscription,
+) (Message, error) {
+	data := templateData{
+		Event:       payload,
+		StatusLabel: statusLabels[payload.Status],
+		TrackURL: publicBaseURL + "/shipping/v1/public/track?tracking_number=" +
+			url.QueryEscape(payload.TrackingNumber),
+		UnsubscribeURL: publicBaseURL + "/shipping/v1/public/track/unsubscribe?token=" +
+			url.QueryEscape(subscription.UnsubscribeToken),
+	}
+	if data.StatusLabel == "" {
+		data.StatusLabel = payload.Status
+	}
+
+	var subject, body strings.Builder
+	if err := subjectTemplate.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("render subject: %w", err)
+	}
+	if err := bodyTemplate.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("render body: %w", err)
+	}
+
+	return Message{
+		Channel:        subscription.Channel,
+		To:             subscription.Target,
+		Subject:        subject.String(),
+		Body:           body.String(),
+		UnsubscribeURL: data.UnsubscribeURL,
+		Event:          payload,
+	}, nil
+}
@@ -0,0 +1,223 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/outbox"
+	"go.uber.org/zap"
+)
+
+// memoryQueue is an in-memory notification queue with the semantics of the
+// Postgres repository: enqueueing fans an event out to the active
+// subscriptions of its shipment, and every claim returns the pending rows.
+type memoryQueue struct {
+	domain.SubscriptionRepository
+
+	mu            sync.Mutex
+	subscriptions []domain.TrackingSubscription
+	nextID        int64
+	pending       map[int64]*domain.NotificationDelivery
+	delivered     []int64
+	retries       int
+	deadLetters   map[int64]string // notification ID → last error
+}
+
+func newMemoryQueue(subscriptions ...domain.TrackingSubscription) *memoryQueue {
+	return &memoryQueue{
+		subscriptions: subscriptions,
+		pending:       map[int64]*domain.NotificationDelivery{},
+		deadLetters:   map[int64]string{},
+	}
+}
+
+func (q *memoryQueue) EnqueueNotifications(_ context.Context, event domain.OutboxEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, sub := range q.subscriptions {
+		if sub.ShipmentID != event.ShipmentID {
+			continue
+		}
+		q.nextID++
+		q.pending[q.nextID] = &domain.NotificationDelivery{
+			ID: q.nextID, Subscription: sub, EventID: event.ID, Payload: event.Payload,
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueue) ClaimNotifications(
+	_ context.Context, limit int, _ time.Duration,
+) ([]domain.NotificationDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []domain.NotificationDelivery
+	for id := range q.nextID + 1 {
+		if d, ok := q.pending[id]; ok && len(out) < limit {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func (q *memoryQueue) MarkNotificationDelivered(_ context.Context, id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, id)
+	q.delivered = append(q.delivered, id)
+	return nil
+}
+
+func (q *memoryQueue) MarkNotificationFailed(_ context.Context, id int64, _ time.Duration, _ string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending[id].Attempts++
+	q.retries++
+	return nil
+}
+
+func (q *memoryQueue) DeadLetterNotification(_ context.Context, id int64, lastError string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, id)
+	q.deadLetters[id] = lastError
+	return nil
+}
+
+// failingNotifier fails every message sent to one target and captures the rest.
+type failingNotifier struct {
+	CaptureNotifier
+	failTo string
+}
+
+func (n *failingNotifier) Send(ctx context.Context, msg Message) error {
+	if msg.To == n.failTo {
+		return errors.New("connection refused")
+	}
+	return n.CaptureNotifier.Send(ctx, msg)
+}
+
+func statusEvent(t *testing.T, eventType string, payload domain.ShipmentEventPayload) domain.OutboxEvent {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	return domain.OutboxEvent{ID: 1, ShipmentID: payload.ShipmentID, EventType: eventType, Payload: raw}
+}
+
+func newTestWorker(q *memoryQueue, notifier Notifier, maxAttempts int) *Worker {
+	return NewWorker(q, notifier, WorkerConfig{
+		PollInterval:  time.Hour,
+		BatchSize:     10,
+		Timeout:       time.Second,
+		MaxAttempts:   maxAttempts,
+		Backoff:       outbox.Backoff{Initial: time.Second, Max: 4 * time.Second},
+		PublicBaseURL: "https://shop.example.com/",
+	}, zap.NewNop())
+}
+
+func TestDispatcherQueuesAndWorkerRenders(t *testing.T) {
+	q := newMemoryQueue(
+		domain.TrackingSubscription{
+			ID: 1, ShipmentID: 2, Channel: domain.ChannelEmail, Target: "alice@example.com", UnsubscribeToken: "tok-1",
+		},
+		domain.TrackingSubscription{
+			ID: 2, ShipmentID: 2, Channel: domain.ChannelWebhook, Target: "https://hooks.example.com/x", UnsubscribeToken: "tok-2",
+		},
+		domain.TrackingSubscription{
+			ID: 3, ShipmentID: 3, Channel: domain.ChannelEmail, Target: "bob@example.com", UnsubscribeToken: "tok-3",
+		},
+	)
+	event := statusEvent(t, domain.OutboxShipmentStatusChanged, domain.ShipmentEventPayload{
+		ShipmentID:     2,
+		TrackingNumber: "9400111899223344556677",
+		Carrier:        "USPS",
+		PreviousStatus: domain.StatusInTransit,
+		Status:         domain.StatusOutForDelivery,
+	})
+	if err := NewDispatcher(q).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(q.pending) != 2 {
+		t.Fatalf("queued %d notifications, want 2", len(q.pending))
+	}
+
+	capture := &CaptureNotifier{}
+	newTestWorker(q, capture, 3).deliverBatch(context.Background())
+
+	messages := capture.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("captured %d messages, want 2", len(messages))
+	}
+	var email Message
+	for _, m := range messages {
+		if m.Channel == domain.ChannelEmail {
+			email = m
+		}
+	}
+	if email.To != "alice@example.com" {
+		t.Errorf("email message To = %q, want alice@example.com", email.To)
+	}
+	if want := "Your shipment 9400111899223344556677 is now out for delivery"; email.Subject != want {
+		t.Errorf("Subject = %q, want %q", email.Subject, want)
+	}
+	if want := "https://shop.example.com/shipping/v1/public/track/unsubscribe?token=tok-1"; email.UnsubscribeURL != want {
+		t.Errorf("UnsubscribeURL = %q, want %q", email.UnsubscribeURL, want)
+	}
+	if !strings.Contains(email.Body, email.UnsubscribeURL) || !strings.Contains(email.Body, "(USPS)") {
+		t.Errorf("Body missing unsubscribe link or carrier:\n%s", email.Body)
+	}
+}
+
+func TestDispatcherIgnoresOtherEvents(t *testing.T) {
+	q := newMemoryQueue(domain.TrackingSubscription{
+		ID: 1, ShipmentID: 2, Channel: domain.ChannelEmail, Target: "a@example.com",
+	})
+
+	event := statusEvent(t, domain.OutboxShipmentCreated, domain.ShipmentEventPayload{ShipmentID: 2})
+	if err := NewDispatcher(q).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(q.pending) != 0 {
+		t.Errorf("queued %d notifications for shipment.created, want 0", len(q.pending))
+	}
+}
+
+func TestWorkerIsolatesFailingSubscriber(t *testing.T) {
+	q := newMemoryQueue(
+		domain.TrackingSubscription{ID: 1, ShipmentID: 2, Channel: domain.ChannelWebhook, Target: "https://dead.example.com"},
+		domain.TrackingSubscription{ID: 2, ShipmentID: 2, Channel: domain.ChannelEmail, Target: "alice@example.com"},
+	)
+	event := statusEvent(t, domain.OutboxShipmentStatusChanged, domain.ShipmentEventPayload{
+		ShipmentID: 2, Status: domain.StatusDelivered,
+	})
+	if err := NewDispatcher(q).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v, want the event published despite the dead subscriber", err)
+	}
+
+	notifier := &failingNotifier{failTo: "https://dead.example.com"}
+	w := newTestWorker(q, notifier, 3)
+	for range 4 {
+		w.deliverBatch(context.Background())
+	}
+
+	if got := notifier.Messages(); len(got) != 1 || got[0].To != "alice@example.com" {
+		t.Errorf("sent = %+v, want exactly one message to alice@example.com", got)
+	}
+	if q.retries != 2 {
+		t.Errorf("retries = %d, want 2 before the dead letter", q.retries)
+	}
+	if lastError, ok := q.deadLetters[1]; !ok || !strings.Contains(lastError, "connection refused") {
+		t.Errorf("dead letter = %q, %v, want the failing subscriber dead-lettered", lastError, ok)
+	}
+	if len(q.pending) != 0 {
+		t.Errorf("pending = %d, want nothing left to retry", len(q.pending))
+	}
+}
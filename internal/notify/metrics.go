@@ -0,0 +1,14 @@
+package notify
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var deliveryAttempts = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "notification_delivery_attempts_total",
+		Help: "Customer notification delivery attempts by result (delivered, retry, dead_letter)",
+	},
+	[]string{"channel", "result"},
+)
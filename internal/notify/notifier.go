@@ -0,0 +1,170 @@
+// Package notify sends tracking update notifications to customers who
+// subscribed on the public track page.
+//
+// The Dispatcher is an outbox publisher: every shipment.status_changed event
+// relayed from shipment_outbox is queued in notification_deliveries for each
+// active subscription. The Worker renders queued notifications through the
+// message templates, sends them through a Notifier, retries failures with
+// exponential backoff and dead-letters those that exhaust their retries.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/netguard"
+	"go.uber.org/zap"
+)
+
+// Message is a rendered notification for a single subscription.
+type Message struct {
+	Channel        string                      // domain.ChannelEmail or domain.ChannelWebhook
+	To             string                      // Email address or webhook URL
+	Subject        string                      // Rendered subject (email)
+	Body           string                      // Rendered text body
+	UnsubscribeURL string                      // One-click unsubscribe link
+	Event          domain.ShipmentEventPayload // Raw event, posted as JSON to webhooks
+}
+
+// Notifier delivers a rendered message.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ChannelNotifier routes messages to a Notifier per channel.
+type ChannelNotifier struct {
+	Email   Notifier
+	Webhook Notifier
+}
+
+func (n *ChannelNotifier) Send(ctx context.Context, msg Message) error {
+	switch msg.Channel {
+	case domain.ChannelEmail:
+		return n.Email.Send(ctx, msg)
+	case domain.ChannelWebhook:
+		return n.Webhook.Send(ctx, msg)
+	default:
+		return fmt.Errorf("unknown notification channel %q", msg.Channel)
+	}
+}
+
+// SMTPNotifier sends email notifications through an SMTP relay.
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier creates an SMTP notifier. Auth is skipped when username is empty
+// (e.g. an in-cluster relay that trusts the pod network).
+func NewSMTPNotifier(host, port, from, username, password string) *SMTPNotifier {
+	n := &SMTPNotifier{addr: host + ":" + port, from: from}
+	if username != "" {
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+func (n *SMTPNotifier) Send(_ context.Context, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "List-Unsubscribe: <%s>\r\n", msg.UnsubscribeURL)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send email to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the event and rendered text to the subscriber's URL.
+// Subscribers are anonymous, so it only connects to public addresses.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{client: netguard.NewClient(timeout)}
+}
+
+func (n *WebhookNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]any{
+		"event":           msg.Event,
+		"message":         msg.Body,
+		"unsubscribe_url": msg.UnsubscribeURL,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post webhook notification: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogNotifier logs messages instead of sending them. Used for the email
+// channel when no SMTP relay is configured (local development).
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Send(_ context.Context, msg Message) error {
+	n.logger.Info("Notification (not sent, no transport configured)",
+		zap.String("channel", msg.Channel),
+		zap.String("subject", msg.Subject),
+		zap.Int("shipment_id", msg.Event.ShipmentID),
+	)
+	return nil
+}
+
+// CaptureNotifier records messages in memory instead of sending them.
+// It stands in for an SMTP capture server (MailHog-style) in tests.
+type CaptureNotifier struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (n *CaptureNotifier) Send(_ context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of the captured messages.
+func (n *CaptureNotifier) Messages() []Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Message(nil), n.messages...)
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/outbox"
+	"go.uber.org/zap"
+)
+
+// WorkerConfig tunes the notification delivery loop.
+type WorkerConfig struct {
+	PollInterval  time.Duration  // How often to look for due notifications
+	BatchSize     int            // Max notifications claimed per poll
+	Timeout       time.Duration  // Timeout per send
+	MaxAttempts   int            // Attempts before a notification is dead-lettered
+	Backoff       outbox.Backoff // Retry delay policy
+	PublicBaseURL string         // External base URL for links in messages
+}
+
+// Worker sends queued customer notifications.
+type Worker struct {
+	repo     domain.SubscriptionRepository
+	notifier Notifier
+	cfg      WorkerConfig
+	logger   *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(repo domain.SubscriptionRepository, notifier Notifier, cfg WorkerConfig, logger *zap.Logger) *Worker {
+	cfg.PublicBaseURL = strings.TrimRight(cfg.PublicBaseURL, "/")
+	return &Worker{
+		repo:     repo,
+		notifier: notifier,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Start runs the delivery loop in a background goroutine until Stop is called.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Go(func() { w.run(ctx) })
+}
+
+// Stop cancels the delivery loop and waits for the in-flight batch to finish.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if w.deliverBatch(ctx) == w.cfg.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch claims due notifications and sends them concurrently, so one
+// slow subscriber does not hold up the rest of the batch.
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	// The lease covers every send in the batch, plus headroom for marking.
+	lease := w.cfg.Timeout + 30*time.Second
+	deliveries, err := w.repo.ClaimNotifications(ctx, w.cfg.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to claim notifications", zap.Error(err))
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Go(func() { w.deliver(ctx, delivery) })
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery domain.NotificationDelivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	err := w.send(sendCtx, delivery)
+	cancel()
+
+	markCtx, markCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer markCancel()
+
+	channel := delivery.Subscription.Channel
+	log := w.logger.With(
+		zap.Int64("notification_id", delivery.ID),
+		zap.Int("subscription_id", delivery.Subscription.ID),
+		zap.Int64("event_id", delivery.EventID),
+		zap.String("channel", channel),
+		zap.Int("attempt", delivery.Attempts+1),
+	)
+
+	switch {
+	case err == nil:
+		deliveryAttempts.WithLabelValues(channel, "delivered").Inc()
+		if markErr := w.repo.MarkNotificationDelivered(markCtx, delivery.ID); markErr != nil {
+			log.Error("Failed to mark notification delivered", zap.Error(markErr))
+		}
+	case delivery.Attempts+1 >= w.cfg.MaxAttempts:
+		deliveryAttempts.WithLabelValues(channel, "dead_letter").Inc()
+		log.Warn("Notification exhausted retries, dead-lettering", zap.Error(err))
+		if markErr := w.repo.DeadLetterNotification(markCtx, delivery.ID, err.Error()); markErr != nil {
+			log.Error("Failed to dead-letter notification", zap.Error(markErr))
+		}
+	default:
+		deliveryAttempts.WithLabelValues(channel, "retry").Inc()
+		retryIn := w.cfg.Backoff.Delay(delivery.Attempts)
+		log.Info("Notification failed, scheduling retry", zap.Duration("retry_in", retryIn), zap.Error(err))
+		if markErr := w.repo.MarkNotificationFailed(markCtx, delivery.ID, retryIn, err.Error()); markErr != nil {
+			log.Error("Failed to record notification failure", zap.Error(markErr))
+		}
+	}
+}
+
+// send renders the notification and hands it to the notifier.
+func (w *Worker) send(ctx context.Context, delivery domain.NotificationDelivery) error {
+	var payload domain.ShipmentEventPayload
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		return fmt.Errorf("decode event %d payload: %w", delivery.EventID, err)
+	}
+	msg, err := render(w.cfg.PublicBaseURL, payload, delivery.Subscription)
+	if err != nil {
+		return err
+	}
+	return w.notifier.Send(ctx, msg)
+}
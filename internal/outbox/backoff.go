@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes exponential retry delays with full jitter.
+//
+// Delay(n) returns a random duration in [0, min(Max, Initial*2^n)], so retries
+// from many events failing at once spread out instead of hitting the
+// downstream in lockstep.
+type Backoff struct {
+	Initial time.Duration // Delay ceiling for the first retry
+	Max     time.Duration // Upper bound for any single delay
+}
+
+// Delay returns the wait before retry number attempt (0-based).
+func (b Backoff) Delay(attempt int) time.Duration {
+	ceiling := b.Initial
+	for range attempt {
+		if ceiling >= b.Max/2 {
+			ceiling = b.Max
+			break
+		}
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, b.Max)
+	if ceiling <= 0 {
+		return 0
+	}
+	//nolint:gosec // jitter does not need a cryptographic source
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 30 * time.Second}
+
+	tests := []struct {
+		attempt     int
+		wantCeiling time.Duration
+	}{
+		{attempt: 0, wantCeiling: time.Second},
+		{attempt: 1, wantCeiling: 2 * time.Second},
+		{attempt: 3, wantCeiling: 8 * time.Second},
+		{attempt: 5, wantCeiling: 30 * time.Second},
+		{attempt: 100, wantCeiling: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 50 {
+			got := b.Delay(tt.attempt)
+			if got < 0 || got > tt.wantCeiling {
+				t.Fatalf("Delay(%d) = %v, want within [0, %v]", tt.attempt, got, tt.wantCeiling)
+			}
+		}
+	}
+}
+
+func TestBackoffDelayZero(t *testing.T) {
+	if got := (Backoff{}).Delay(3); got != 0 {
+		t.Errorf("zero Backoff Delay() = %v, want 0", got)
+	}
+}
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	eventsPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_events_published_total",
+			Help: "Outbox events delivered to the publisher",
+		},
+		[]string{"event_type"},
+	)
+
+	publishFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_publish_failures_total",
+			Help: "Outbox delivery attempts that failed and were scheduled for retry",
+		},
+		[]string{"event_type"},
+	)
+
+	eventsDeadLettered = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_events_dead_lettered_total",
+			Help: "Outbox events that exhausted their delivery attempts and are no longer retried",
+		},
+		[]string{"event_type"},
+	)
+
+	// Time from the business change (row insert) to successful delivery.
+	deliveryLag = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "outbox_delivery_lag_seconds",
+			Help:    "Time between an outbox event being written and being delivered",
+			Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 300, 900, 3600},
+		},
+	)
+)
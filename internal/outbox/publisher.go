@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// Publisher delivers a single outbox event to downstream consumers.
+// Returning an error schedules the event for a retry, so implementations
+// must tolerate receiving the same event more than once.
+type Publisher interface {
+	Publish(ctx context.Context, event domain.OutboxEvent) error
+}
+
+// Envelope is the wire format published for every outbox event.
+// Consumers dedupe on ID.
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	ShipmentID int             `json:"shipment_id"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps an outbox event in the published wire format.
+func NewEnvelope(event domain.OutboxEvent) Envelope {
+	return Envelope{
+		ID:         event.ID,
+		Type:       event.EventType,
+		ShipmentID: event.ShipmentID,
+		OccurredAt: event.CreatedAt.UTC().Format(time.RFC3339),
+		Data:       event.Payload,
+	}
+}
+
+// MultiPublisher fans an event out to several publishers. Every publisher is
+// called even if an earlier one fails; any failure retries the event for all
+// of them, which is safe because publishers must already tolerate duplicates.
+// Publishers that fan out to subscribers should queue per subscriber (see
+// webhook.Dispatcher), so one unreachable subscriber cannot fail the event.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookPublisher POSTs each event as JSON to a fixed URL.
+// Any 2xx response counts as delivered.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	body, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", event.ID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post event %d: %w", event.ID, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post event %d: unexpected status %d", event.ID, resp.StatusCode)
+	}
+	return nil
+}
+
+// WriterPublisher writes each event as a JSON line to an io.Writer
+// (stdout or a file). Intended for local development without consumers.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event domain.OutboxEvent) error {
+	line, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", event.ID, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write event %d: %w", event.ID, err)
+	}
+	return nil
+}
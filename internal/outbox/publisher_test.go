@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	var got struct {
+		header   http.Header
+		envelope Envelope
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.header = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got.envelope); err != nil {
+			t.Errorf("body %q: %v", body, err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	event := testEvent(42)
+	if err := NewWebhookPublisher(srv.URL, time.Second).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if got.header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", got.header.Get("Content-Type"))
+	}
+	if got.header.Get("X-Event-Id") != "42" || got.header.Get("X-Event-Type") != event.EventType {
+		t.Errorf("X-Event-Id = %q, X-Event-Type = %q", got.header.Get("X-Event-Id"), got.header.Get("X-Event-Type"))
+	}
+	if got.envelope.ID != 42 || got.envelope.Type != event.EventType || got.envelope.ShipmentID != event.ShipmentID {
+		t.Errorf("envelope = %+v", got.envelope)
+	}
+	if string(got.envelope.Data) != string(event.Payload) {
+		t.Errorf("envelope data = %s, want %s", got.envelope.Data, event.Payload)
+	}
+}
+
+func TestWebhookPublisherErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) }},
+		{"3xx is not delivered", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotModified) }},
+		{"timeout", func(_ http.ResponseWriter, r *http.Request) {
+			// Read the body so the server notices when the client gives up.
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			err := NewWebhookPublisher(srv.URL, 50*time.Millisecond).Publish(context.Background(), testEvent(1))
+			if err == nil {
+				t.Error("Publish() error = nil, want the event retried")
+			}
+		})
+	}
+}
+
+func TestWriterPublisherFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher := NewWriterPublisher(f)
+	for _, id := range []int64{1, 2} {
+		if err := publisher.Publish(context.Background(), testEvent(id)); err != nil {
+			t.Fatalf("Publish(%d) error = %v", id, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var ids []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var envelope Envelope
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, envelope.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("event IDs = %v, want one JSON line per event [1 2]", ids)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriterPublisherWriteError(t *testing.T) {
+	if err := NewWriterPublisher(failingWriter{}).Publish(context.Background(), testEvent(1)); err == nil {
+		t.Error("Publish() error = nil, want the write error")
+	}
+}
+
+func TestMultiPublisherCallsEveryPublisher(t *testing.T) {
+	failing, ok := &flakyPublisher{failures: 1}, &flakyPublisher{}
+	if err := (MultiPublisher{failing, ok}).Publish(context.Background(), testEvent(1)); err == nil {
+		t.Error("Publish() error = nil, want the failure reported")
+	}
+	if failing.calls != 1 || ok.calls != 1 {
+		t.Errorf("calls = %d, %d, want both publishers called", failing.calls, ok.calls)
+	}
+}
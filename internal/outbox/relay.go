@@ -0,0 +1,160 @@
+// Package outbox relays shipment events from the shipment_outbox table to
+// downstream consumers.
+//
+// Shipment writes insert an outbox row in the same transaction as the change
+// (see postgres.ShipmentRepository). The Relay polls for due rows, hands each
+// one to a Publisher and marks it delivered, or schedules a retry with
+// exponential backoff when publishing fails. Delivery is at-least-once; an
+// event that still fails after MaxAttempts is dead-lettered.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// RelayConfig tunes the relay polling loop.
+type RelayConfig struct {
+	PollInterval   time.Duration // How often to look for due events
+	BatchSize      int           // Max events claimed per poll
+	Lease          time.Duration // How long a claimed event is hidden from other relays
+	PublishTimeout time.Duration // Timeout for a single Publish call
+	MaxAttempts    int           // Publish attempts before an event is dead-lettered
+	Backoff        Backoff       // Retry delay policy for failed events
+}
+
+// Relay delivers outbox events to a Publisher.
+type Relay struct {
+	repo      domain.OutboxRepository
+	publisher Publisher
+	cfg       RelayConfig
+	logger    *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRelay(repo domain.OutboxRepository, publisher Publisher, cfg RelayConfig, logger *zap.Logger) *Relay {
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Start runs the polling loop in a background goroutine until Stop is called.
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Go(func() { r.run(ctx) })
+}
+
+// Stop cancels the polling loop and waits for the in-flight batch to finish.
+// Events claimed but not yet marked are retried once their lease expires.
+func (r *Relay) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+// LagCheck returns a readiness check that fails when the oldest undelivered
+// event is older than maxLag, i.e. consumers are down or the relay is stuck.
+func (r *Relay) LagCheck(maxLag time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		age, err := r.repo.OldestPendingAge(ctx)
+		if err != nil {
+			return err
+		}
+		if age > maxLag {
+			return fmt.Errorf("oldest undelivered event is %s old (max %s)", age.Round(time.Second), maxLag)
+		}
+		return nil
+	}
+}
+
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch means more events are likely due: poll again right away.
+		if r.relayBatch(ctx) == r.cfg.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch claims and publishes one batch, returning the number of events claimed.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	events, err := r.repo.Claim(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Failed to claim outbox events", zap.Error(err))
+		}
+		return 0
+	}
+
+	for _, event := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		r.deliver(ctx, event)
+	}
+
+	return len(events)
+}
+
+func (r *Relay) deliver(ctx context.Context, event domain.OutboxEvent) {
+	publishCtx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+	err := r.publisher.Publish(publishCtx, event)
+	cancel()
+
+	// Mark with a context that survives shutdown so a published event is not
+	// left to be re-sent just because Stop was called mid-batch.
+	markCtx, markCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer markCancel()
+
+	log := r.logger.With(
+		zap.Int64("event_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Int("attempt", event.Attempts+1),
+	)
+
+	switch {
+	case err == nil:
+		if markErr := r.repo.MarkDelivered(markCtx, event.ID); markErr != nil {
+			// The event will be re-sent after the lease expires; consumers dedupe on ID.
+			log.Error("Failed to mark outbox event delivered", zap.Error(markErr))
+			return
+		}
+		eventsPublished.WithLabelValues(event.EventType).Inc()
+		deliveryLag.Observe(time.Since(event.CreatedAt).Seconds())
+	case event.Attempts+1 >= r.cfg.MaxAttempts:
+		eventsDeadLettered.WithLabelValues(event.EventType).Inc()
+		log.Error("Outbox event exhausted retries, dead-lettering", zap.Error(err))
+		if markErr := r.repo.DeadLetter(markCtx, event.ID, err.Error()); markErr != nil {
+			log.Error("Failed to dead-letter outbox event", zap.Error(markErr))
+		}
+	default:
+		publishFailures.WithLabelValues(event.EventType).Inc()
+		retryIn := r.cfg.Backoff.Delay(event.Attempts)
+		log.Warn("Outbox publish failed, scheduling retry", zap.Duration("retry_in", retryIn), zap.Error(err))
+		if markErr := r.repo.MarkFailed(markCtx, event.ID, retryIn, err.Error()); markErr != nil {
+			log.Error("Failed to record outbox failure", zap.Error(markErr))
+		}
+	}
+}
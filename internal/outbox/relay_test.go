@@ -0,0 +1,244 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+// memoryOutbox is an in-memory domain.OutboxRepository: every claim returns
+// the pending events, ignoring next_attempt_at, so each relayBatch call is
+// one delivery attempt.
+type memoryOutbox struct {
+	mu          sync.Mutex
+	pending     map[int64]*domain.OutboxEvent
+	delivered   []int64
+	retries     []time.Duration
+	deadLetters map[int64]string // event ID → last error
+	oldest      time.Duration
+	claimErr    error
+}
+
+func newMemoryOutbox(events ...domain.OutboxEvent) *memoryOutbox {
+	o := &memoryOutbox{pending: map[int64]*domain.OutboxEvent{}, deadLetters: map[int64]string{}}
+	for _, e := range events {
+		o.pending[e.ID] = &e
+	}
+	return o
+}
+
+func (o *memoryOutbox) Claim(_ context.Context, limit int, _ time.Duration) ([]domain.OutboxEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.claimErr != nil {
+		return nil, o.claimErr
+	}
+	var out []domain.OutboxEvent
+	for _, e := range o.pending {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, *e)
+	}
+	return out, nil
+}
+
+func (o *memoryOutbox) MarkDelivered(_ context.Context, id int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.pending, id)
+	o.delivered = append(o.delivered, id)
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(_ context.Context, id int64, retryIn time.Duration, _ string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending[id].Attempts++
+	o.retries = append(o.retries, retryIn)
+	return nil
+}
+
+func (o *memoryOutbox) DeadLetter(_ context.Context, id int64, lastError string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.pending, id)
+	o.deadLetters[id] = lastError
+	return nil
+}
+
+func (o *memoryOutbox) OldestPendingAge(context.Context) (time.Duration, error) {
+	return o.oldest, nil
+}
+
+func (o *memoryOutbox) deliveredCount() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.delivered)
+}
+
+// flakyPublisher fails the first failures calls, then succeeds.
+type flakyPublisher struct {
+	failures int
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *flakyPublisher) Publish(context.Context, domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("consumer unavailable")
+	}
+	return nil
+}
+
+func newTestRelay(repo domain.OutboxRepository, publisher Publisher, maxAttempts int) *Relay {
+	return NewRelay(repo, publisher, RelayConfig{
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+		Lease:          time.Minute,
+		PublishTimeout: time.Second,
+		MaxAttempts:    maxAttempts,
+		Backoff:        Backoff{Initial: time.Second, Max: 4 * time.Second},
+	}, zap.NewNop())
+}
+
+func testEvent(id int64) domain.OutboxEvent {
+	return domain.OutboxEvent{
+		ID: id, ShipmentID: 1, EventType: domain.OutboxShipmentStatusChanged,
+		Payload: []byte(`{"shipment_id":1,"status":"delivered"}`), CreatedAt: time.Now(),
+	}
+}
+
+func TestRelayRetriesWithBackoffUntilDelivered(t *testing.T) {
+	repo := newMemoryOutbox(testEvent(1))
+	publisher := &flakyPublisher{failures: 2}
+	relay := newTestRelay(repo, publisher, 5)
+
+	published := eventsPublished.WithLabelValues(domain.OutboxShipmentStatusChanged)
+	failures := publishFailures.WithLabelValues(domain.OutboxShipmentStatusChanged)
+	publishedBefore, failuresBefore := testutil.ToFloat64(published), testutil.ToFloat64(failures)
+
+	for range 3 {
+		if n := relay.relayBatch(context.Background()); n != 1 {
+			t.Fatalf("relayBatch() = %d, want 1", n)
+		}
+	}
+
+	if len(repo.delivered) != 1 || len(repo.pending) != 0 {
+		t.Fatalf("delivered = %v, pending = %d, want the event delivered", repo.delivered, len(repo.pending))
+	}
+	// Full jitter: retry n waits at most min(Max, Initial*2^n).
+	ceilings := []time.Duration{time.Second, 2 * time.Second}
+	if len(repo.retries) != len(ceilings) {
+		t.Fatalf("retries = %v, want %d", repo.retries, len(ceilings))
+	}
+	for i, delay := range repo.retries {
+		if delay < 0 || delay > ceilings[i] {
+			t.Errorf("retry %d delay = %v, want within [0, %v]", i, delay, ceilings[i])
+		}
+	}
+	if got := testutil.ToFloat64(published) - publishedBefore; got != 1 {
+		t.Errorf("published = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(failures) - failuresBefore; got != 2 {
+		t.Errorf("publish failures = %v, want 2", got)
+	}
+}
+
+func TestRelayDeadLettersAfterMaxAttempts(t *testing.T) {
+	repo := newMemoryOutbox(testEvent(1))
+	publisher := &flakyPublisher{failures: 100}
+	relay := newTestRelay(repo, publisher, 3)
+
+	deadLettered := eventsDeadLettered.WithLabelValues(domain.OutboxShipmentStatusChanged)
+	before := testutil.ToFloat64(deadLettered)
+
+	for range 4 {
+		relay.relayBatch(context.Background())
+	}
+
+	if publisher.calls != 3 {
+		t.Errorf("publish calls = %d, want 3 (MaxAttempts)", publisher.calls)
+	}
+	if len(repo.retries) != 2 {
+		t.Errorf("retries = %v, want 2 before the dead letter", repo.retries)
+	}
+	if lastError, ok := repo.deadLetters[1]; !ok || lastError != "consumer unavailable" {
+		t.Errorf("dead letter = %q, %v, want the last error recorded", lastError, ok)
+	}
+	if len(repo.pending) != 0 {
+		t.Errorf("pending = %d, want the dead letter no longer claimed", len(repo.pending))
+	}
+	if got := testutil.ToFloat64(deadLettered) - before; got != 1 {
+		t.Errorf("dead-lettered = %v, want 1", got)
+	}
+}
+
+func TestRelayClaimError(t *testing.T) {
+	repo := newMemoryOutbox(testEvent(1))
+	repo.claimErr = errors.New("connection refused")
+	publisher := &flakyPublisher{}
+
+	if n := newTestRelay(repo, publisher, 5).relayBatch(context.Background()); n != 0 {
+		t.Errorf("relayBatch() = %d, want 0", n)
+	}
+	if publisher.calls != 0 {
+		t.Errorf("publish calls = %d, want 0", publisher.calls)
+	}
+}
+
+func TestRelayStartStop(t *testing.T) {
+	var events []domain.OutboxEvent
+	for id := range int64(25) {
+		events = append(events, testEvent(id+1))
+	}
+	repo := newMemoryOutbox(events...)
+	// PollInterval is an hour: draining 25 events with BatchSize 10 relies on
+	// full batches being followed by an immediate poll.
+	relay := newTestRelay(repo, &flakyPublisher{}, 5)
+	relay.Start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for repo.deliveredCount() != len(events) {
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered %d events, want %d", repo.deliveredCount(), len(events))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		relay.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
+
+func TestRelayLagCheck(t *testing.T) {
+	repo := newMemoryOutbox()
+	check := newTestRelay(repo, &flakyPublisher{}, 5).LagCheck(time.Minute)
+
+	repo.oldest = 30 * time.Second
+	if err := check(context.Background()); err != nil {
+		t.Errorf("lag 30s: err = %v, want nil", err)
+	}
+	repo.oldest = 2 * time.Minute
+	if err := check(context.Background()); err == nil || !strings.Contains(err.Error(), "2m0s old") {
+		t.Errorf("lag 2m: err = %v, want the lag reported", err)
+	}
+}
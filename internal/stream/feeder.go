@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// FeederConfig tunes the shipment_events tail.
+type FeederConfig struct {
+	PollInterval time.Duration // How often to look for new events
+	BatchSize    int           // Max events read per poll
+
+	// OnEvent, if set, sees every event before it is published, e.g. to
+	// invalidate cached shipments changed by another replica.
+	OnEvent func(domain.TrackingEvent)
+}
+
+// Feeder tails shipment_events and publishes new rows to the Hub. Every
+// replica runs its own Feeder; the cursor starts at the newest event, so
+// streams only carry changes made after the replica started.
+//
+// Delivery is best effort: event IDs can commit out of order under concurrent
+// writes, so an event may occasionally be skipped. Clients recover the current
+// state from the snapshot sent on every (re)connect.
+type Feeder struct {
+	repo   domain.TrackingEventRepository
+	hub    *Hub
+	cfg    FeederConfig
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewFeeder(repo domain.TrackingEventRepository, hub *Hub, cfg FeederConfig, logger *zap.Logger) *Feeder {
+	return &Feeder{
+		repo:   repo,
+		hub:    hub,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Start runs the tail loop in a background goroutine until Stop is called.
+func (f *Feeder) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.wg.Go(func() { f.run(ctx) })
+}
+
+// Stop cancels the tail loop and waits for it to exit.
+func (f *Feeder) Stop() {
+	if f.cancel == nil {
+		return
+	}
+	f.cancel()
+	f.wg.Wait()
+}
+
+func (f *Feeder) run(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+
+	cursor, started := 0, false
+	for {
+		if !started {
+			latest, err := f.repo.LatestID(ctx)
+			switch {
+			case err == nil:
+				cursor, started = latest, true
+			case ctx.Err() == nil:
+				f.logger.Error("Failed to read tracking event cursor", zap.Error(err))
+			}
+		}
+
+		if started {
+			var n int
+			cursor, n = f.poll(ctx, cursor)
+			if n == f.cfg.BatchSize && ctx.Err() == nil {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll publishes events after cursor and returns the new cursor and the number read.
+func (f *Feeder) poll(ctx context.Context, cursor int) (int, int) {
+	events, err := f.repo.ListSince(ctx, cursor, f.cfg.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			f.logger.Error("Failed to read tracking events", zap.Error(err), zap.Int("cursor", cursor))
+		}
+		return cursor, 0
+	}
+
+	for _, event := range events {
+		if f.cfg.OnEvent != nil {
+			f.cfg.OnEvent(event)
+		}
+		f.hub.Publish(event)
+		cursor = event.ID
+	}
+	eventsFed.Add(float64(len(events)))
+
+	return cursor, len(events)
+}
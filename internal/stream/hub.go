@@ -0,0 +1,125 @@
+// Package stream fans shipment tracking events out to live subscribers
+// (the public Server-Sent Events endpoint).
+//
+// The Hub is an in-process pub/sub keyed by tracking number. It is fed by the
+// Feeder, which tails the shipment_events table, so every replica sees every
+// change regardless of which replica handled the write.
+package stream
+
+import (
+	"sync"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+// subscriberBuffer is the per-subscriber queue. A subscriber that falls this
+// far behind is dropped; SSE clients reconnect and re-read the current state.
+const subscriberBuffer = 16
+
+// Subscription receives events for a single tracking number.
+// Events is closed when the subscription is cancelled, the subscriber falls
+// behind, or the hub is closed.
+type Subscription struct {
+	Events <-chan domain.TrackingEvent
+
+	events chan domain.TrackingEvent
+	key    string
+}
+
+// Hub is an in-process pub/sub of tracking events keyed by tracking number.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe registers for events of trackingNumber. ok is false once the hub
+// has been closed (the service is shutting down).
+func (h *Hub) Subscribe(trackingNumber string) (sub *Subscription, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, false
+	}
+
+	events := make(chan domain.TrackingEvent, subscriberBuffer)
+	sub = &Subscription{Events: events, events: events, key: trackingNumber}
+	if h.subs[trackingNumber] == nil {
+		h.subs[trackingNumber] = make(map[*Subscription]struct{})
+	}
+	h.subs[trackingNumber][sub] = struct{}{}
+	activeSubscribers.Inc()
+
+	return sub, true
+}
+
+// Unsubscribe removes sub and closes its channel. It is safe to call more than once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// Publish delivers event to every subscriber of event.TrackingNumber without blocking.
+func (h *Hub) Publish(event domain.TrackingEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[event.TrackingNumber] {
+		select {
+		case sub.events <- event:
+		default:
+			droppedSubscribers.Inc()
+			h.remove(sub)
+		}
+	}
+}
+
+// Close disconnects every subscriber and rejects new ones. Called on shutdown
+// so long-lived streams do not hold http.Server.Shutdown open.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// Len returns the number of active subscribers.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for _, subs := range h.subs {
+		n += len(subs)
+	}
+	return n
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(sub *Subscription) {
+	subs, ok := h.subs[sub.key]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.key)
+	}
+	close(sub.events)
+	activeSubscribers.Dec()
+}
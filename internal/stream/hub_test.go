@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+func TestHubPublishRoutesByTrackingNumber(t *testing.T) {
+	hub := NewHub()
+	sub, ok := hub.Subscribe("TRK-1")
+	if !ok {
+		t.Fatal("Subscribe() on open hub returned ok=false")
+	}
+	other, _ := hub.Subscribe("TRK-2")
+
+	hub.Publish(domain.TrackingEvent{ID: 1, TrackingNumber: "TRK-1", Status: domain.StatusInTransit})
+
+	select {
+	case event := <-sub.Events:
+		if event.ID != 1 {
+			t.Errorf("received event %d, want 1", event.ID)
+		}
+	default:
+		t.Fatal("subscriber of TRK-1 received nothing")
+	}
+
+	select {
+	case event := <-other.Events:
+		t.Errorf("subscriber of TRK-2 received event %d", event.ID)
+	default:
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("TRK-1")
+
+	for i := range subscriberBuffer + 1 {
+		hub.Publish(domain.TrackingEvent{ID: i + 1, TrackingNumber: "TRK-1"})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before drop, want %d", received, subscriberBuffer)
+	}
+	if n := hub.Len(); n != 0 {
+		t.Errorf("Len() = %d after drop, want 0", n)
+	}
+}
+
+func TestHubUnsubscribeIsIdempotent(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("TRK-1")
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub)
+
+	if _, open := <-sub.Events; open {
+		t.Error("Events still open after Unsubscribe")
+	}
+}
+
+func TestHubCloseDisconnectsSubscribers(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("TRK-1")
+
+	hub.Close()
+
+	if _, open := <-sub.Events; open {
+		t.Error("Events still open after Close")
+	}
+	if _, ok := hub.Subscribe("TRK-1"); ok {
+		t.Error("Subscribe() after Close returned ok=true")
+	}
+	hub.Unsubscribe(sub)
+}
@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	activeSubscribers = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "tracking_stream_subscribers",
+			Help: "Live tracking stream subscribers connected to this replica",
+		},
+	)
+
+	droppedSubscribers = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "tracking_stream_dropped_subscribers_total",
+			Help: "Tracking stream subscribers disconnected for falling behind",
+		},
+	)
+
+	eventsFed = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "tracking_stream_events_total",
+			Help: "Tracking events read from shipment_events and published to the hub",
+		},
+	)
+)
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// shipmentETag is the strong ETag of a shipment's full representation: its
+// version, which every change to the shipment bumps.
+func shipmentETag(shipment *domain.Shipment) string {
+	return `"` + strconv.Itoa(shipment.Version) + `"`
+}
+
+// statusOnlyETag tags the status-only tracking view, whose body differs from
+// the full one at the same version.
+func statusOnlyETag(shipment *domain.Shipment) string {
+	return `"` + strconv.Itoa(shipment.Version) + `-status"`
+}
+
+// notModified sets the ETag and answers 304 when If-None-Match already has it,
+// so pollers skip the body. The comparison is weak (RFC 9110 §13.1.2).
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersions reads If-Match as the shipment versions the caller accepts.
+// It returns nil (no precondition) when the header is absent or "*". Weak or
+// foreign tags never match strongly (RFC 9110 §13.1.1), so a header with no
+// usable tag yields an empty, non-nil list that always fails.
+func ifMatchVersions(c *gin.Context) []int {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return nil
+	}
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+		unquoted, ok := strings.CutPrefix(tag, `"`)
+		if !ok {
+			continue
+		}
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+		if !ok {
+			continue
+		}
+		if v, err := strconv.Atoi(unquoted); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// versionedRepo serves one shipment and applies the If-Match check like the
+// Postgres repository.
+type versionedRepo struct {
+	domain.ShipmentRepository
+	shipment domain.Shipment
+}
+
+func (r *versionedRepo) GetByTrackingNumber(_ context.Context, trackingNumber string) (*domain.Shipment, error) {
+	if trackingNumber != r.shipment.TrackingNumber {
+		return nil, domain.ErrShipmentNotFound
+	}
+	s := r.shipment
+	return &s, nil
+}
+
+func (r *versionedRepo) GetByOrderID(_ context.Context, orderID string) (*domain.Shipment, error) {
+	if orderID != fmt.Sprint(r.shipment.OrderID) {
+		return nil, domain.ErrShipmentNotFound
+	}
+	s := r.shipment
+	return &s, nil
+}
+
+func (r *versionedRepo) UpdateStatus(
+	_ context.Context, _ int, status, _ string, ifVersion []int,
+) (*domain.StatusUpdate, error) {
+	if ifVersion != nil && !slices.Contains(ifVersion, r.shipment.Version) {
+		return nil, domain.ErrVersionMismatch
+	}
+	previous := r.shipment.Status
+	r.shipment.Status = status
+	r.shipment.Version++
+	s := r.shipment
+	return &domain.StatusUpdate{Shipment: &s, PreviousStatus: previous}, nil
+}
+
+func newVersionedRouter(verifier *logicv1.OwnershipVerifier) *gin.Engine {
+	repo := &versionedRepo{shipment: domain.Shipment{
+		ID: 1, OrderID: 7, TrackingNumber: "TRK1", Status: domain.StatusInTransit, Version: 3,
+	}}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, Handlers{Shipping: NewHandler(logicv1.NewShippingService(repo), verifier)})
+	return r
+}
+
+func TestConditionalGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		verifier    *logicv1.OwnershipVerifier
+		ifNoneMatch string
+		wantStatus  int
+		wantETag    string
+	}{
+		{"track without validator", "/shipping/v1/public/track?tracking_number=TRK1", nil, "", http.StatusOK, `"3"`},
+		{"track unchanged", "/shipping/v1/public/track?tracking_number=TRK1", nil, `"3"`, http.StatusNotModified, `"3"`},
+		{"track weak validator", "/shipping/v1/public/track?tracking_number=TRK1", nil, `W/"3"`, http.StatusNotModified, `"3"`},
+		{"track changed", "/shipping/v1/public/track?tracking_number=TRK1", nil, `"2"`, http.StatusOK, `"3"`},
+		{"track list", "/shipping/v1/public/track?tracking_number=TRK1", nil, `"1", "3"`, http.StatusNotModified, `"3"`},
+		{
+			"status-only view has its own tag", "/shipping/v1/public/track?tracking_number=TRK1",
+			logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), 0), `"3"`, http.StatusOK, `"3-status"`,
+		},
+		{"order unchanged", "/shipping/v1/internal/orders/7", nil, `"3"`, http.StatusNotModified, `"3"`},
+		{"order any", "/shipping/v1/internal/orders/7", nil, "*", http.StatusNotModified, `"3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newVersionedRouter(tt.verifier)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 has a body: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIfMatchOnStatusUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+		wantETag   string
+	}{
+		{"no precondition", "", http.StatusOK, `"4"`},
+		{"current version", `"3"`, http.StatusOK, `"4"`},
+		{"any version", "*", http.StatusOK, `"4"`},
+		{"one of several", `"2", "3"`, http.StatusOK, `"4"`},
+		{"stale version", `"2"`, http.StatusPreconditionFailed, ""},
+		{"weak tag never matches", `W/"3"`, http.StatusPreconditionFailed, ""},
+		{"malformed tag", `3`, http.StatusPreconditionFailed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newVersionedRouter(nil)
+			req := httptest.NewRequest(http.MethodPut, "/shipping/v1/internal/shipments/1/status",
+				strings.NewReader(`{"status":"delivered"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+			if tt.wantStatus == http.StatusPreconditionFailed &&
+				!strings.Contains(w.Body.String(), problemTypeBase+"precondition-failed") {
+				t.Errorf("body = %s, want a precondition-failed problem", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type ExceptionHandler struct {
+	service *logicv1.ExceptionService
+}
+
+func NewExceptionHandler(service *logicv1.ExceptionService) *ExceptionHandler {
+	return &ExceptionHandler{
+		service: service,
+	}
+}
+
+// RaiseException handles POST /shipping/v1/internal/shipments/:shipmentId/exceptions
+// Body: {"reason_code": "...", "description": "..."}
+func (h *ExceptionHandler) RaiseException(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	shipmentID, err := strconv.Atoi(c.Param("shipmentId"))
+	if err != nil {
+		respondValidation(c, "Invalid path parameter", FieldError{Field: "shipmentId", Message: "must be an integer"})
+		return
+	}
+
+	var req domain.RaiseExceptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	exception, err := h.service.RaiseException(ctx, shipmentID, req.ReasonCode, req.Description)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to raise shipment exception", zap.Error(err), zap.Int("shipment_id", shipmentID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipment exception raised",
+		zap.Int("shipment_id", shipmentID),
+		zap.Int("exception_id", exception.ID),
+		zap.String("reason_code", exception.ReasonCode),
+	)
+	c.JSON(http.StatusCreated, exception)
+}
+
+// ListOpenExceptions handles GET /shipping/v1/internal/exceptions
+// Query params: limit (optional, max 100)
+func (h *ExceptionHandler) ListOpenExceptions(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			respondValidation(c, "Invalid query parameter", FieldError{Field: "limit", Message: "must be a non-negative integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	exceptions, err := h.service.ListOpenExceptions(ctx, limit)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to list open exceptions", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exceptions": exceptions})
+}
+
+// ResolveException handles POST /shipping/v1/internal/exceptions/:exceptionId/resolve
+// Body: {"resolution": "...", "resolved_by": "..."}
+func (h *ExceptionHandler) ResolveException(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	exceptionID, err := strconv.Atoi(c.Param("exceptionId"))
+	if err != nil {
+		respondValidation(c, "Invalid path parameter", FieldError{Field: "exceptionId", Message: "must be an integer"})
+		return
+	}
+
+	var req domain.ResolveExceptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	exception, err := h.service.ResolveException(ctx, exceptionID, req.Resolution, req.ResolvedBy)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to resolve shipment exception", zap.Error(err), zap.Int("exception_id", exceptionID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipment exception resolved",
+		zap.Int("exception_id", exceptionID),
+		zap.Int("shipment_id", exception.ShipmentID),
+		zap.String("resolution", exception.Resolution),
+	)
+	c.JSON(http.StatusOK, exception)
+}
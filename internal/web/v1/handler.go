@@ -1,148 +1,278 @@
-package v1
-
-import (
-	"errors"
-	"net/http"
-	"strconv"
-
-	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
-	"github.com/duynhne/shipping-service/middleware"
-	"github.com/gin-gonic/gin"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
-	"go.uber.org/zap"
-)
-
-type Handler struct {
-	service *logicv1.ShippingService
-}
-
-func NewHandler(service *logicv1.ShippingService) *Handler {
-	return &Handler{
-		service: service,
-	}
-}
-
-func (h *Handler) TrackShipment(c *gin.Context) {
-	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
-		attribute.String("layer", "web"),
-		attribute.String("method", c.Request.Method),
-		attribute.String("path", c.Request.URL.Path),
-	))
-	defer span.End()
-
-	zapLogger := middleware.GetLoggerFromGinContext(c)
-
-	// Accept both tracking_number (preferred, per API docs) and trackingId (legacy)
-	trackingID := c.Query("tracking_number")
-	if trackingID == "" {
-		trackingID = c.Query("trackingId") // Backward compatibility
-	}
-	span.SetAttributes(attribute.String("tracking.id", trackingID))
-
-	shipment, err := h.service.TrackShipment(ctx, trackingID)
-	if err != nil {
-		span.RecordError(err)
-		zapLogger.Error("Failed to track shipment", zap.Error(err))
-
-		switch {
-		case errors.Is(err, logicv1.ErrShipmentNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "Shipment not found"})
-		case errors.Is(err, logicv1.ErrCarrierUnavailable):
-			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Carrier unavailable"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
-		return
-	}
-
-	zapLogger.Info("Shipment tracked", zap.String("tracking_id", trackingID))
-	c.JSON(http.StatusOK, shipment)
-}
-
-// EstimateShipping handles GET /shipping/v1/public/estimate
-// Query params: origin, destination, weight
-func (h *Handler) EstimateShipping(c *gin.Context) {
-	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
-		attribute.String("layer", "web"),
-		attribute.String("method", c.Request.Method),
-		attribute.String("path", c.Request.URL.Path),
-	))
-	defer span.End()
-
-	zapLogger := middleware.GetLoggerFromGinContext(c)
-
-	origin := c.Query("origin")
-	destination := c.Query("destination")
-	weightStr := c.Query("weight")
-
-	// Validate required params
-	if origin == "" || destination == "" || weightStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Missing required parameters: origin, destination, weight",
-		})
-		return
-	}
-
-	// Parse weight
-	weight, err := strconv.ParseFloat(weightStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid weight value"})
-		return
-	}
-
-	span.SetAttributes(
-		attribute.String("estimate.origin", origin),
-		attribute.String("estimate.destination", destination),
-		attribute.Float64("estimate.weight", weight),
-	)
-
-	estimate, err := h.service.EstimateShipping(ctx, origin, destination, weight)
-	if err != nil {
-		span.RecordError(err)
-		zapLogger.Error("Failed to estimate shipping", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
-	}
-
-	zapLogger.Info("Shipping estimated",
-		zap.String("origin", origin),
-		zap.String("destination", destination),
-		zap.Float64("weight", weight),
-		zap.Float64("cost", estimate.EstimatedCost),
-	)
-	c.JSON(http.StatusOK, estimate)
-}
-
-// GetShipmentByOrder handles GET /shipping/v1/internal/orders/:orderId
-// Returns shipment info for a given order ID
-func (h *Handler) GetShipmentByOrder(c *gin.Context) {
-	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
-		attribute.String("layer", "web"),
-		attribute.String("method", c.Request.Method),
-		attribute.String("path", c.Request.URL.Path),
-	))
-	defer span.End()
-
-	zapLogger := middleware.GetLoggerFromGinContext(c)
-
-	orderID := c.Param("orderId")
-	span.SetAttributes(attribute.String("order.id", orderID))
-
-	shipment, err := h.service.GetShipmentByOrderID(ctx, orderID)
-	if err != nil {
-		span.RecordError(err)
-		zapLogger.Error("Failed to get shipment by order", zap.Error(err), zap.String("order_id", orderID))
-
-		switch {
-		case errors.Is(err, logicv1.ErrShipmentNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "Shipment not found for this order"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		}
-		return
-	}
-
-	zapLogger.Info("Shipment retrieved by order", zap.String("order_id", orderID), zap.Int("shipment_id", shipment.ID))
-	c.JSON(http.StatusOK, shipment)
-}
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   *logicv1.ShippingService
+	ownership *logicv1.OwnershipVerifier // nil when tracking verification is disabled
+}
+
+func NewHandler(service *logicv1.ShippingService, ownership *logicv1.OwnershipVerifier) *Handler {
+	return &Handler{
+		service:   service,
+		ownership: ownership,
+	}
+}
+
+// CustomerTokenHeader carries a customer token on public tracking requests.
+const CustomerTokenHeader = "X-Customer-Token"
+
+// ownershipProof reads the optional proof of a public tracking request:
+// postal_code, and customer_token (query, for links in emails) or X-Customer-Token.
+func ownershipProof(c *gin.Context) logicv1.OwnershipProof {
+	token := c.GetHeader(CustomerTokenHeader)
+	if token == "" {
+		token = c.Query("customer_token")
+	}
+	return logicv1.OwnershipProof{PostalCode: c.Query("postal_code"), Token: token}
+}
+
+func (h *Handler) TrackShipment(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	// Accept both tracking_number (preferred, per API docs) and trackingId (legacy)
+	trackingID := c.Query("tracking_number")
+	if trackingID == "" {
+		trackingID = c.Query("trackingId") // Backward compatibility
+	}
+	span.SetAttributes(attribute.String("tracking.id", trackingID))
+
+	shipment, err := h.service.TrackShipment(ctx, trackingID)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to track shipment", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+
+	full, err := h.ownership.Authorize(shipment, ownershipProof(c))
+	if err != nil {
+		zapLogger.Warn("Tracking ownership proof rejected", zap.String("tracking_id", trackingID))
+		respondError(c, err)
+		return
+	}
+	span.SetAttributes(attribute.Bool("tracking.full_details", full))
+
+	zapLogger.Info("Shipment tracked", zap.String("tracking_id", trackingID), zap.Bool("full_details", full))
+	// The representation depends on the ownership proof, which may be a header
+	c.Header("Vary", CustomerTokenHeader)
+	if !full {
+		if notModified(c, statusOnlyETag(shipment)) {
+			return
+		}
+		c.JSON(http.StatusOK, logicv1.StatusOnly(shipment))
+		return
+	}
+	if notModified(c, shipmentETag(shipment)) {
+		return
+	}
+	c.JSON(http.StatusOK, shipment)
+}
+
+// EstimateShipping handles GET /shipping/v1/public/estimate
+// Query params: origin, destination, weight
+func (h *Handler) EstimateShipping(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	origin := c.Query("origin")
+	destination := c.Query("destination")
+	weightStr := c.Query("weight")
+
+	// Validate required params
+	if fields := missingQuery(c, "origin", "destination", "weight"); len(fields) > 0 {
+		respondValidation(c, "Missing required query parameters", fields...)
+		return
+	}
+
+	// Parse weight
+	weight, err := strconv.ParseFloat(weightStr, 64)
+	if err != nil {
+		respondValidation(c, "Invalid query parameter", FieldError{Field: "weight", Message: "must be a number"})
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("estimate.origin", origin),
+		attribute.String("estimate.destination", destination),
+		attribute.Float64("estimate.weight", weight),
+	)
+
+	estimate, err := h.service.EstimateShipping(ctx, origin, destination, weight)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to estimate shipping", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipping estimated",
+		zap.String("origin", origin),
+		zap.String("destination", destination),
+		zap.Float64("weight", weight),
+		zap.Float64("cost", estimate.EstimatedCost),
+	)
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetShipmentByOrder handles GET /shipping/v1/internal/orders/:orderId
+// Returns shipment info for a given order ID
+func (h *Handler) GetShipmentByOrder(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	orderID := c.Param("orderId")
+	span.SetAttributes(attribute.String("order.id", orderID))
+
+	shipment, err := h.service.GetShipmentByOrderID(ctx, orderID)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to get shipment by order", zap.Error(err), zap.String("order_id", orderID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipment retrieved by order", zap.String("order_id", orderID), zap.Int("shipment_id", shipment.ID))
+	if notModified(c, shipmentETag(shipment)) {
+		return
+	}
+	c.JSON(http.StatusOK, shipment)
+}
+
+// IssueCustomerToken handles POST /shipping/v1/internal/orders/:orderId/tracking-token
+// Returns a token that unlocks full public tracking details for the order's shipment
+func (h *Handler) IssueCustomerToken(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	if h.ownership == nil {
+		respondUnavailable(c, "Tracking verification is disabled")
+		return
+	}
+
+	orderID := c.Param("orderId")
+	span.SetAttributes(attribute.String("order.id", orderID))
+
+	shipment, err := h.service.GetShipmentByOrderID(ctx, orderID)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to issue customer token", zap.Error(err), zap.String("order_id", orderID))
+
+		respondError(c, err)
+		return
+	}
+
+	token := h.ownership.IssueToken(shipment.OrderID)
+	zapLogger.Info("Customer token issued", zap.String("order_id", orderID), zap.String("expires_at", token.ExpiresAt))
+	c.JSON(http.StatusCreated, token)
+}
+
+// CreateShipment handles POST /shipping/v1/internal/shipments
+// Body: {"order_id": 1, "tracking_number": "...", "carrier": "...", "estimated_delivery": "RFC3339", "destination_postal_code": "..."}
+func (h *Handler) CreateShipment(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	var req domain.CreateShipmentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	shipment, err := h.service.CreateShipment(ctx, &req)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to create shipment", zap.Error(err), zap.Int("order_id", req.OrderID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipment created", zap.Int("order_id", req.OrderID), zap.Int("shipment_id", shipment.ID))
+	c.Header("ETag", shipmentETag(shipment))
+	c.JSON(http.StatusCreated, shipment)
+}
+
+// UpdateShipmentStatus handles PUT /shipping/v1/internal/shipments/:shipmentId/status
+// Body: {"status": "in_transit", "description": "..."}
+func (h *Handler) UpdateShipmentStatus(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	shipmentID, err := strconv.Atoi(c.Param("shipmentId"))
+	if err != nil {
+		respondValidation(c, "Invalid path parameter", FieldError{Field: "shipmentId", Message: "must be an integer"})
+		return
+	}
+
+	var req domain.UpdateStatusRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// If-Match rejects the update when the shipment changed since the caller read it
+	shipment, err := h.service.UpdateShipmentStatus(ctx, shipmentID, req.Status, req.Description, ifMatchVersions(c))
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to update shipment status", zap.Error(err), zap.Int("shipment_id", shipmentID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Shipment status updated",
+		zap.Int("shipment_id", shipmentID),
+		zap.String("status", shipment.Status),
+	)
+	c.Header("ETag", shipmentETag(shipment))
+	c.JSON(http.StatusOK, shipment)
+}
@@ -0,0 +1,231 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader lets a client retry a mutating request safely
+// (IETF draft-ietf-httpapi-idempotency-key-header).
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed from an earlier request.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const (
+	maxIdempotencyKeyLength = 255
+	idempotencyStoreTimeout = 5 * time.Second
+	idempotencyPurgeEvery   = 10 * time.Minute
+)
+
+// replayedHeaders are the response headers stored with the body and replayed.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// IdempotencyConfig configures Idempotency.
+type IdempotencyConfig struct {
+	TTL          time.Duration // How long a key and its response are kept
+	LockTimeout  time.Duration // How long a request in flight holds its key
+	MaxBodyBytes int64         // Largest request body read to fingerprint the request
+}
+
+// Idempotency makes mutating routes safe to retry. The first request with an
+// Idempotency-Key runs and its response is stored; a repeat with the same key
+// and body gets the stored response back with Idempotent-Replayed: true.
+// Reusing a key for a different body is a 422; repeating it while the first
+// request is still running is a 409. The body is read into memory to
+// fingerprint it, so one larger than MaxBodyBytes is a 413.
+//
+// Keys are scoped to the caller and route, so two services cannot collide.
+// 5xx responses are not stored, so the client can retry with the same key.
+type Idempotency struct {
+	repo domain.IdempotencyRepository
+	cfg  IdempotencyConfig
+	now  func() time.Time
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+// NewIdempotency creates the Idempotency-Key handler.
+func NewIdempotency(repo domain.IdempotencyRepository, cfg IdempotencyConfig) *Idempotency {
+	return &Idempotency{repo: repo, cfg: cfg, now: time.Now}
+}
+
+// idempotent returns the middleware for a route; a nil Idempotency
+// (IDEMPOTENCY_ENABLED=false) lets every request through.
+func idempotent(i *Idempotency) gin.HandlerFunc {
+	if i == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return i.handle
+}
+
+func (i *Idempotency) handle(c *gin.Context) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		respondValidation(c, "", FieldError{
+			Field:   IdempotencyKeyHeader,
+			Message: "must be at most " + strconv.Itoa(maxIdempotencyKeyLength) + " characters",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, i.cfg.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeProblem(c, problemRequestTooLarge,
+				"Request body must be at most "+strconv.FormatInt(tooLarge.Limit, 10)+" bytes", nil)
+			return
+		}
+		respondValidation(c, "Request body could not be read")
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	logger := middleware.GetLoggerFromGinContext(c)
+	scope := callerSubject(c) + " " + c.Request.Method + " " + c.FullPath()
+	fingerprint := requestFingerprint(c.Request, body)
+
+	owner := rand.Text() // This attempt's hold on the key, which a retry may take over after LockTimeout
+	existing, err := i.repo.Claim(c.Request.Context(), scope, key, owner, fingerprint, i.cfg.LockTimeout, i.cfg.TTL)
+	if err != nil {
+		// Running the request without the key could create a duplicate; let the client retry.
+		logger.Error("Failed to claim idempotency key", zap.Error(err))
+		respondUnavailable(c, "Idempotency-Key could not be checked, retry later")
+		return
+	}
+	i.maybePurge(logger)
+
+	if existing != nil {
+		switch {
+		case existing.Fingerprint != fingerprint:
+			writeProblem(c, problemIdempotencyMismatch, "", nil)
+		case existing.InFlight():
+			c.Header("Retry-After", "1")
+			writeProblem(c, problemIdempotencyInUse, "", nil)
+		default:
+			replay(c, existing)
+		}
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	// Store the outcome even if the client has gone away meanwhile.
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), idempotencyStoreTimeout)
+	defer cancel()
+
+	completed := false
+	defer func() {
+		// A 5xx or a panic leaves nothing to replay: free the key for a retry.
+		if !completed {
+			if err := i.repo.Release(storeCtx, scope, key, owner); err != nil {
+				logger.Error("Failed to release idempotency key", zap.Error(err))
+			}
+		}
+	}()
+
+	c.Next()
+
+	status := recorder.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	record := &domain.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		StatusCode:  status,
+		Header:      make(map[string]string, len(replayedHeaders)),
+		Body:        recorder.body.Bytes(),
+	}
+	for _, h := range replayedHeaders {
+		if v := recorder.Header().Get(h); v != "" {
+			record.Header[h] = v
+		}
+	}
+	if err := i.repo.Complete(storeCtx, scope, key, owner, record); err != nil {
+		logger.Error("Failed to store idempotent response", zap.Error(err))
+		return
+	}
+	completed = true
+}
+
+func replay(c *gin.Context, record *domain.IdempotencyRecord) {
+	for h, v := range record.Header {
+		c.Header(h, v)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(record.StatusCode)
+	_, _ = c.Writer.Write(record.Body)
+	c.Abort()
+}
+
+// maybePurge deletes expired keys at most once per idempotencyPurgeEvery in
+// this replica; expired keys are also taken over when reused.
+func (i *Idempotency) maybePurge(logger *zap.Logger) {
+	now := i.now()
+	i.mu.Lock()
+	if now.Sub(i.lastPurge) < idempotencyPurgeEvery {
+		i.mu.Unlock()
+		return
+	}
+	i.lastPurge = now
+	i.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+		defer cancel()
+		if n, err := i.repo.DeleteExpired(ctx); err != nil {
+			logger.Warn("Failed to purge expired idempotency keys", zap.Error(err))
+		} else if n > 0 {
+			logger.Debug("Purged expired idempotency keys", zap.Int64("count", n))
+		}
+	}()
+}
+
+// requestFingerprint hashes what makes two requests "the same": method, path
+// (which carries the resource IDs) and the exact body bytes.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body while writing it through.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
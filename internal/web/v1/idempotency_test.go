@@ -0,0 +1,308 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/auth"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyRepo keeps records in memory; locks and TTLs never run out
+// unless stale is set, which lets the next claim take over an in-flight key.
+type fakeIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+	owners  map[string]string
+	stale   bool
+}
+
+func newFakeIdempotencyRepo() *fakeIdempotencyRepo {
+	return &fakeIdempotencyRepo{records: make(map[string]*domain.IdempotencyRecord), owners: make(map[string]string)}
+}
+
+func (r *fakeIdempotencyRepo) Claim(
+	_ context.Context, scope, key, owner, fingerprint string, _, _ time.Duration,
+) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[scope+"|"+key]; ok && !(r.stale && existing.InFlight()) {
+		copied := *existing
+		return &copied, nil
+	}
+	r.records[scope+"|"+key] = &domain.IdempotencyRecord{Fingerprint: fingerprint}
+	r.owners[scope+"|"+key] = owner
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepo) Complete(
+	_ context.Context, scope, key, owner string, record *domain.IdempotencyRecord,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[scope+"|"+key] == owner {
+		r.records[scope+"|"+key] = record
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) Release(_ context.Context, scope, key, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[scope+"|"+key]; ok && existing.InFlight() && r.owners[scope+"|"+key] == owner {
+		delete(r.records, scope+"|"+key)
+	}
+	return nil
+}
+
+func (r *fakeIdempotencyRepo) DeleteExpired(context.Context) (int64, error) { return 0, nil }
+
+type idempotentRequest struct {
+	key        string
+	apiKey     string
+	path       string
+	body       string
+	wantStatus int
+	wantReplay bool
+}
+
+func TestIdempotency(t *testing.T) {
+	const created = "/shipping/v1/internal/shipments"
+
+	tests := []struct {
+		name      string
+		handler   func(c *gin.Context) // Defaults to a 201 with a new ID per call
+		inFlight  bool                 // Seed the key as held by a running request
+		maxBody   int64                // Defaults to 1 KiB
+		requests  []idempotentRequest
+		wantCalls int
+	}{
+		{
+			name: "no key runs every request",
+			requests: []idempotentRequest{
+				{body: `{"a":1}`, wantStatus: http.StatusCreated},
+				{body: `{"a":1}`, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "repeat replays the stored response",
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusCreated},
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusCreated, wantReplay: true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "same key with a different body",
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusCreated},
+				{key: "k1", body: `{"a":2}`, wantStatus: http.StatusUnprocessableEntity},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:     "same key while the first request runs",
+			inFlight: true,
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusConflict},
+			},
+		},
+		{
+			name: "client errors are replayed",
+			handler: func(c *gin.Context) {
+				respondValidation(c, "bad")
+			},
+			requests: []idempotentRequest{
+				{key: "k1", body: `{}`, wantStatus: http.StatusBadRequest},
+				{key: "k1", body: `{}`, wantStatus: http.StatusBadRequest, wantReplay: true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "server errors release the key",
+			handler: func(c *gin.Context) {
+				respondUnavailable(c, "down")
+			},
+			requests: []idempotentRequest{
+				{key: "k1", body: `{}`, wantStatus: http.StatusServiceUnavailable},
+				{key: "k1", body: `{}`, wantStatus: http.StatusServiceUnavailable},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "keys are scoped to the caller",
+			requests: []idempotentRequest{
+				{key: "k1", apiKey: "writer-key", body: `{"a":1}`, wantStatus: http.StatusCreated},
+				{key: "k1", apiKey: "other-writer-key", body: `{"a":2}`, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "keys are scoped to the route",
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusCreated},
+				{key: "k1", path: "/other", body: `{"a":2}`, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "key too long",
+			requests: []idempotentRequest{
+				{key: strings.Repeat("k", maxIdempotencyKeyLength+1), body: `{}`, wantStatus: http.StatusBadRequest},
+			},
+		},
+		{
+			name:    "body too large",
+			maxBody: 8,
+			requests: []idempotentRequest{
+				{key: "k1", body: `{"a":"123456789"}`, wantStatus: http.StatusRequestEntityTooLarge},
+				{body: `{"a":"123456789"}`, wantStatus: http.StatusCreated},
+				{key: "k1", body: `{"a":1}`, wantStatus: http.StatusCreated},
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeIdempotencyRepo()
+			maxBody := tt.maxBody
+			if maxBody == 0 {
+				maxBody = 1 << 10
+			}
+			idem := NewIdempotency(repo, IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute, MaxBodyBytes: maxBody})
+			authenticator := auth.New(auth.Config{APIKeys: []auth.APIKey{
+				{Name: "order-service", Key: "writer-key", Scopes: []string{auth.ScopeShipmentsWrite}},
+				{Name: "carrier-sync", Key: "other-writer-key", Scopes: []string{auth.ScopeShipmentsWrite}},
+			}})
+
+			calls := 0
+			handler := func(c *gin.Context) {
+				calls++
+				if tt.handler != nil {
+					tt.handler(c)
+					return
+				}
+				c.Header("Location", "/shipments/"+strings.Repeat("1", calls))
+				c.JSON(http.StatusCreated, gin.H{"call": calls})
+			}
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.POST(created, requireScope(authenticator, auth.ScopeShipmentsWrite), idempotent(idem), handler)
+			r.POST("/other", requireScope(authenticator, auth.ScopeShipmentsWrite), idempotent(idem), handler)
+
+			if tt.inFlight {
+				_, _ = repo.Claim(context.Background(), "order-service POST "+created, "k1", "other-attempt",
+					requestFingerprint(httptest.NewRequest(http.MethodPost, created, nil), []byte(`{"a":1}`)), 0, 0)
+			}
+
+			var first *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				path := req.path
+				if path == "" {
+					path = created
+				}
+				httpReq := httptest.NewRequest(http.MethodPost, path, strings.NewReader(req.body))
+				apiKey := req.apiKey
+				if apiKey == "" {
+					apiKey = "writer-key"
+				}
+				httpReq.Header.Set(auth.APIKeyHeader, apiKey)
+				if req.key != "" {
+					httpReq.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httpReq)
+
+				if w.Code != req.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d (%s)", i, w.Code, req.wantStatus, w.Body.String())
+				}
+				replayed := w.Header().Get(IdempotentReplayedHeader) == "true"
+				if replayed != req.wantReplay {
+					t.Errorf("request %d: replayed = %v, want %v", i, replayed, req.wantReplay)
+				}
+				if req.wantReplay {
+					for _, h := range []string{"Content-Type", "Location"} {
+						if got, want := w.Header().Get(h), first.Header().Get(h); got != want {
+							t.Errorf("request %d: %s = %q, want %q", i, h, got, want)
+						}
+					}
+					if w.Body.String() != first.Body.String() {
+						t.Errorf("request %d: body = %s, want %s", i, w.Body.String(), first.Body.String())
+					}
+				}
+				if first == nil {
+					first = w
+				}
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyProblemTypes(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	idem := NewIdempotency(repo, IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute, MaxBodyBytes: 1 << 10})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/shipments", idempotent(idem), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/shipments", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	serve(`{"a":1}`)
+	w := serve(`{"a":2}`)
+
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Type != problemTypeBase+"idempotency-key-mismatch" || problem.Status != http.StatusUnprocessableEntity {
+		t.Errorf("problem = %+v", problem)
+	}
+}
+
+func TestIdempotencyTakeoverKeepsNewOwner(t *testing.T) {
+	repo := newFakeIdempotencyRepo()
+	idem := NewIdempotency(repo, IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute, MaxBodyBytes: 1 << 10})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/shipments", idempotent(idem), func(c *gin.Context) {
+		// The lock runs out mid-request and a retry takes the key over before this attempt fails.
+		repo.stale = true
+		_, _ = repo.Claim(context.Background(), " POST /shipments", "k1", "retry", "fingerprint", 0, 0)
+		respondUnavailable(c, "down")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/shipments", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	record, ok := repo.records[" POST /shipments|k1"]
+	if !ok || !record.InFlight() || repo.owners[" POST /shipments|k1"] != "retry" {
+		t.Errorf("key after the first attempt released it = %+v (owner %q), want held by the retry",
+			record, repo.owners[" POST /shipments|k1"])
+	}
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevel is the log level of the replica serving the request.
+type LogLevel struct {
+	Level string `json:"level" binding:"required"` // debug, info, warn or error
+}
+
+// LogLevelHandler reads and changes the log level at runtime, without a
+// restart. The change applies to the replica that serves the request only.
+type LogLevelHandler struct {
+	level zap.AtomicLevel
+}
+
+func NewLogLevelHandler(level zap.AtomicLevel) *LogLevelHandler {
+	return &LogLevelHandler{level: level}
+}
+
+// GetLogLevel handles GET /shipping/v1/internal/log-level
+func (h *LogLevelHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, LogLevel{Level: h.level.Level().String()})
+}
+
+// SetLogLevel handles PUT /shipping/v1/internal/log-level
+// Body: {"level": "debug"}
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req LogLevel
+	if !bindJSON(c, &req) {
+		return
+	}
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil || level < zapcore.DebugLevel || level > zapcore.ErrorLevel {
+		respondValidation(c, "", FieldError{Field: "level", Message: "must be one of debug, info, warn, error"})
+		return
+	}
+
+	previous := h.level.Level()
+	h.level.SetLevel(level)
+	middleware.GetLoggerFromGinContext(c).Warn("Log level changed",
+		zap.Stringer("from", previous), zap.Stringer("to", level), zap.String("by", callerSubject(c)))
+	c.JSON(http.StatusOK, LogLevel{Level: level.String()})
+}
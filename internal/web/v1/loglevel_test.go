@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogLevelEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantLevel  zapcore.Level
+	}{
+		{"get", http.MethodGet, "", http.StatusOK, zapcore.InfoLevel},
+		{"set debug", http.MethodPut, `{"level":"debug"}`, http.StatusOK, zapcore.DebugLevel},
+		{"case insensitive", http.MethodPut, `{"level":"WARN"}`, http.StatusOK, zapcore.WarnLevel},
+		{"unknown level", http.MethodPut, `{"level":"verbose"}`, http.StatusBadRequest, zapcore.InfoLevel},
+		{"fatal is refused", http.MethodPut, `{"level":"fatal"}`, http.StatusBadRequest, zapcore.InfoLevel},
+		{"missing level", http.MethodPut, `{}`, http.StatusBadRequest, zapcore.InfoLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			RegisterRoutes(r, Handlers{LogLevel: NewLogLevelHandler(level)})
+
+			req := httptest.NewRequest(tt.method, "/shipping/v1/internal/log-level", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if level.Level() != tt.wantLevel {
+				t.Errorf("level = %s, want %s", level.Level(), tt.wantLevel)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(w.Body.String(), `"level":"`+tt.wantLevel.String()+`"`) {
+				t.Errorf("body = %s, want level %s", w.Body.String(), tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package v1
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type openAPIDoc struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Parameters []struct {
+		Name     string `json:"name"`
+		In       string `json:"in"`
+		Required bool   `json:"required"`
+	} `json:"parameters"`
+	Responses map[string]struct {
+		Ref     string `json:"$ref"`
+		Content map[string]struct {
+			Schema openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `json:"$ref"`
+	Type       string                   `json:"type"`
+	Required   []string                 `json:"required"`
+	Properties map[string]openAPISchema `json:"properties"`
+	OneOf      []openAPISchema          `json:"oneOf"`
+}
+
+var ginParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+func loadOpenAPI(t *testing.T) openAPIDoc {
+	t.Helper()
+	var doc openAPIDoc
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return doc
+}
+
+// newTestRouter registers the v1 routes with nil handlers; they are never invoked.
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, Handlers{})
+	return r
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	doc := loadOpenAPI(t)
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi = %q, want 3.x", doc.OpenAPI)
+	}
+
+	handlerQueries := queryParamsByHandler(t)
+	registered := map[string]bool{}
+	for _, route := range newTestRouter().Routes() {
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+
+		op, ok := doc.Paths[path][method]
+		if !ok {
+			t.Errorf("%s %s is registered but missing from openapi.json", route.Method, path)
+			continue
+		}
+
+		var wantParams []string
+		for _, m := range ginParam.FindAllStringSubmatch(route.Path, -1) {
+			wantParams = append(wantParams, m[1])
+		}
+		var gotParams []string
+		for _, p := range op.Parameters {
+			if p.In != "path" {
+				continue
+			}
+			if !p.Required {
+				t.Errorf("%s %s: path parameter %q must be required", route.Method, path, p.Name)
+			}
+			gotParams = append(gotParams, p.Name)
+		}
+		slices.Sort(wantParams)
+		slices.Sort(gotParams)
+		if !slices.Equal(gotParams, wantParams) {
+			t.Errorf("%s %s: path parameters = %v, want %v", route.Method, path, gotParams, wantParams)
+		}
+
+		var docQuery []string
+		for _, p := range op.Parameters {
+			if p.In == "query" {
+				docQuery = append(docQuery, p.Name)
+			}
+		}
+		readQuery := handlerQueries[handlerKey(route.Handler)]
+		slices.Sort(docQuery)
+		slices.Sort(readQuery)
+		if !slices.Equal(docQuery, readQuery) {
+			t.Errorf("%s %s: documented query parameters = %v, handler %s reads %v",
+				route.Method, path, docQuery, handlerKey(route.Handler), readQuery)
+		}
+
+		if len(op.Responses) == 0 {
+			t.Errorf("%s %s: no responses documented", route.Method, path)
+		}
+	}
+
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.json documents %s %s, which is not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// handlerKey turns a Gin handler name such as
+// ".../web/v1.(*Handler).TrackShipment-fm" into "Handler.TrackShipment".
+func handlerKey(name string) string {
+	name = strings.TrimSuffix(name[strings.LastIndex(name, "/v1.")+len("/v1."):], "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// queryParamsByHandler reads this package's source and returns, per function
+// or method ("Handler.TrackShipment"), the query parameters it reads: string
+// literals passed to c.Query, c.DefaultQuery, c.GetQuery or missingQuery,
+// including through the package's own helpers it calls.
+func queryParamsByHandler(t *testing.T) map[string][]string {
+	t.Helper()
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range sources {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		files = append(files, file)
+	}
+
+	direct := map[string][]string{}
+	calls := map[string][]string{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			key, recv := fn.Name.Name, ""
+			if fn.Recv != nil && len(fn.Recv.List) == 1 {
+				typ := fn.Recv.List[0].Type
+				if star, ok := typ.(*ast.StarExpr); ok {
+					typ = star.X
+				}
+				key = typ.(*ast.Ident).Name + "." + key
+				if names := fn.Recv.List[0].Names; len(names) == 1 {
+					recv = names[0].Name
+				}
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				switch f := call.Fun.(type) {
+				case *ast.Ident:
+					if f.Name == "missingQuery" {
+						direct[key] = append(direct[key], stringLiterals(call.Args[1:])...)
+					}
+					calls[key] = append(calls[key], f.Name)
+				case *ast.SelectorExpr:
+					switch f.Sel.Name {
+					case "Query", "DefaultQuery", "GetQuery":
+						direct[key] = append(direct[key], stringLiterals(call.Args[:1])...)
+					}
+					if x, ok := f.X.(*ast.Ident); ok && recv != "" && x.Name == recv {
+						calls[key] = append(calls[key], strings.SplitN(key, ".", 2)[0]+"."+f.Sel.Name)
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	out := map[string][]string{}
+	var collect func(key string, seen map[string]bool) []string
+	collect = func(key string, seen map[string]bool) []string {
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		params := slices.Clone(direct[key])
+		for _, callee := range calls[key] {
+			params = append(params, collect(callee, seen)...)
+		}
+		return params
+	}
+	for key := range direct {
+		out[key] = nil
+	}
+	for key := range calls {
+		params := collect(key, map[string]bool{})
+		slices.Sort(params)
+		out[key] = slices.Compact(params)
+	}
+	return out
+}
+
+func stringLiterals(exprs []ast.Expr) []string {
+	var out []string
+	for _, e := range exprs {
+		if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if s, err := strconv.Unquote(lit.Value); err == nil {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func TestOpenAPIResponseSchemas(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	tests := []struct {
+		method, path, status string
+		schemas              []string // The $ref, or each oneOf alternative
+	}{
+		{"get", "/shipping/v1/public/track", "200", []string{"Shipment", "ShipmentStatus"}},
+		{"get", "/shipping/v1/internal/orders/{orderId}", "200", []string{"Shipment"}},
+		{"post", "/shipping/v1/internal/orders/{orderId}/tracking-token", "201", []string{"CustomerToken"}},
+		{"post", "/shipping/v1/internal/shipments", "201", []string{"Shipment"}},
+		{"put", "/shipping/v1/internal/shipments/{shipmentId}/status", "200", []string{"Shipment"}},
+		{"get", "/shipping/v1/public/estimate", "200", []string{"EstimateResponse"}},
+	}
+	for _, tt := range tests {
+		schema := doc.Paths[tt.path][tt.method].Responses[tt.status].Content["application/json"].Schema
+		got := []string{schema.Ref}
+		if len(schema.OneOf) > 0 {
+			got = got[:0]
+			for _, alt := range schema.OneOf {
+				got = append(got, alt.Ref)
+			}
+		}
+		var want []string
+		for _, name := range tt.schemas {
+			want = append(want, "#/components/schemas/"+name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s %s %s: schema = %q, want %q", tt.method, tt.path, tt.status, got, want)
+		}
+	}
+
+	for name, typ := range map[string]reflect.Type{
+		"Shipment":          reflect.TypeFor[domain.Shipment](),
+		"ShipmentException": reflect.TypeFor[domain.ShipmentException](),
+		"EstimateResponse":  reflect.TypeFor[domain.EstimateResponse](),
+		"ShipmentStatus":    reflect.TypeFor[domain.ShipmentStatus](),
+		"CustomerToken":     reflect.TypeFor[domain.CustomerToken](),
+		"LogLevel":          reflect.TypeFor[LogLevel](),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertSchemaMatches(t, doc.Components.Schemas[name], typ)
+		})
+	}
+}
+
+// assertSchemaMatches checks that schema has exactly the JSON fields of typ, with
+// matching types, and that fields without omitempty are required.
+func assertSchemaMatches(t *testing.T, schema openAPISchema, typ reflect.Type) {
+	t.Helper()
+
+	fields := map[string]bool{}
+	for field := range typ.Fields() {
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = true
+
+		prop, ok := schema.Properties[name]
+		if !ok {
+			t.Errorf("field %q missing from schema", name)
+			continue
+		}
+		if want := openAPIType(field.Type); prop.Type != want && !(want == "object" && prop.Ref != "") {
+			t.Errorf("field %q: type = %q, want %q", name, prop.Type, want)
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		if required := slices.Contains(schema.Required, name); required == omitempty {
+			t.Errorf("field %q: required = %v, want %v", name, required, !omitempty)
+		}
+	}
+
+	for name := range schema.Properties {
+		if !fields[name] {
+			t.Errorf("schema property %q has no matching struct field", name)
+		}
+	}
+}
+
+func openAPIType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/shipping/v1/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if !json.Valid(w.Body.Bytes()) {
+		t.Error("body is not valid JSON")
+	}
+}
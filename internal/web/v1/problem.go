@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType is the RFC 7807 media type of every error response.
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the stable, machine-readable problem type of every
+// error response; clients should switch on Type, not on Title or Detail.
+const problemTypeBase = "urn:shipping-service:problem:"
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"` // Validation failures, one per field
+}
+
+// FieldError describes one invalid request field or parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type problemKind struct {
+	slug   string
+	title  string
+	status int
+}
+
+var (
+	problemValidation  = problemKind{"validation-error", "Request validation failed", http.StatusBadRequest}
+	problemInternal    = problemKind{"internal-error", "Internal server error", http.StatusInternalServerError}
+	problemUnavailable = problemKind{"service-unavailable", "Service unavailable", http.StatusServiceUnavailable}
+
+	problemUnauthenticated = problemKind{"unauthenticated", "Authentication required", http.StatusUnauthorized}
+
+	problemIdempotencyInUse = problemKind{"idempotency-key-in-use",
+		"A request with this Idempotency-Key is still in progress", http.StatusConflict}
+	problemIdempotencyMismatch = problemKind{"idempotency-key-mismatch",
+		"Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity}
+	problemRequestTooLarge = problemKind{"request-too-large", "Request body too large", http.StatusRequestEntityTooLarge}
+)
+
+// problemKinds maps logicv1 sentinel errors to problem types. It is the single
+// place where business errors become HTTP statuses.
+var problemKinds = []struct {
+	err  error
+	kind problemKind
+}{
+	{logicv1.ErrShipmentNotFound, problemKind{"shipment-not-found", "Shipment not found", http.StatusNotFound}},
+	{logicv1.ErrInvalidAddress, problemKind{"invalid-address", "Invalid address", http.StatusBadRequest}},
+	{logicv1.ErrCarrierUnavailable, problemKind{"carrier-unavailable", "Carrier unavailable", http.StatusServiceUnavailable}},
+	{logicv1.ErrUnauthorized, problemKind{"forbidden", "Not allowed to perform this operation", http.StatusForbidden}},
+	{logicv1.ErrExceptionNotFound, problemKind{"exception-not-found", "Shipment exception not found", http.StatusNotFound}},
+	{logicv1.ErrInvalidExceptionReason, problemKind{"invalid-exception-reason", "Invalid exception reason", http.StatusBadRequest}},
+	{logicv1.ErrExceptionConflict, problemKind{"exception-conflict", "Shipment exception state conflict", http.StatusConflict}},
+	{logicv1.ErrShipmentExists, problemKind{"shipment-exists", "Shipment already exists", http.StatusConflict}},
+	{logicv1.ErrInvalidShipment, problemKind{"invalid-shipment", "Invalid shipment", http.StatusBadRequest}},
+	{logicv1.ErrInvalidStatusTransition, problemKind{"invalid-status-transition", "Status transition not allowed", http.StatusConflict}},
+	{logicv1.ErrInvalidSubscription, problemKind{"invalid-subscription", "Invalid subscription", http.StatusBadRequest}},
+	{logicv1.ErrSubscriptionNotFound, problemKind{"subscription-not-found", "Subscription not found", http.StatusNotFound}},
+	{logicv1.ErrInvalidWebhook, problemKind{"invalid-webhook", "Invalid webhook subscription", http.StatusBadRequest}},
+	{logicv1.ErrWebhookNotFound, problemKind{"webhook-not-found", "Webhook not found", http.StatusNotFound}},
+	{logicv1.ErrWebhookInactive, problemKind{"webhook-inactive", "Webhook subscription is inactive", http.StatusConflict}},
+	{logicv1.ErrPreconditionFailed, problemKind{"precondition-failed", "Shipment was modified since the If-Match version", http.StatusPreconditionFailed}},
+	{logicv1.ErrInvalidBatch, problemKind{"invalid-batch", "Invalid batch request", http.StatusBadRequest}},
+}
+
+// respondError writes the problem for a logic-layer error. Unknown errors
+// become a 500; error messages never reach clients.
+func respondError(c *gin.Context, err error) {
+	kind := problemInternal
+	for _, k := range problemKinds {
+		if errors.Is(err, k.err) {
+			kind = k.kind
+			break
+		}
+	}
+	var fieldErr *logicv1.FieldError
+	var fields []FieldError
+	if errors.As(err, &fieldErr) {
+		fields = []FieldError{{Field: fieldErr.Field, Message: fieldErr.Message}}
+	}
+
+	writeProblem(c, kind, "", fields)
+}
+
+// respondValidation writes a validation-error problem for invalid parameters or body fields.
+func respondValidation(c *gin.Context, detail string, fields ...FieldError) {
+	writeProblem(c, problemValidation, detail, fields)
+}
+
+// respondUnavailable writes a service-unavailable problem.
+func respondUnavailable(c *gin.Context, detail string) {
+	writeProblem(c, problemUnavailable, detail, nil)
+}
+
+// bindJSON binds the request body into req, writing a validation problem and
+// returning false on failure. Field names in the problem are the JSON names.
+func bindJSON(c *gin.Context, req any) bool {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return true
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		respondValidation(c, "Request body is not valid JSON for this operation")
+		return false
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{Field: jsonFieldName(req, fe.StructField()), Message: validationMessage(fe)})
+	}
+	respondValidation(c, "Request body has invalid fields", fields...)
+	return false
+}
+
+// missingQuery returns a field error for each empty query parameter, in order.
+func missingQuery(c *gin.Context, names ...string) []FieldError {
+	var fields []FieldError
+	for _, name := range names {
+		if c.Query(name) == "" {
+			fields = append(fields, FieldError{Field: name, Message: "is required"})
+		}
+	}
+	return fields
+}
+
+func writeProblem(c *gin.Context, kind problemKind, detail string, fields []FieldError) {
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(kind.status, Problem{
+		Type:     problemTypeBase + kind.slug,
+		Title:    kind.title,
+		Status:   kind.status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		TraceID:  traceID(c),
+		Errors:   fields,
+	})
+}
+
+// traceID prefers the ID LoggingMiddleware already issued (and echoed in
+// X-Trace-ID), so a problem can be matched to its log lines.
+func traceID(c *gin.Context) string {
+	if id, ok := c.Get("trace_id"); ok {
+		if s, ok := id.(string); ok {
+			return s
+		}
+	}
+	return middleware.GetTraceID(c)
+}
+
+// jsonFieldName returns the JSON name of a top-level field of the struct req points to.
+func jsonFieldName(req any, structField string) string {
+	typ := reflect.TypeOf(req)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if field, ok := typ.FieldByName(structField); ok {
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return structField
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	default:
+		return "failed the " + fe.Tag() + " check"
+	}
+}
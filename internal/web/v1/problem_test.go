@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/gin-gonic/gin"
+)
+
+// serveProblem runs handler behind a stub of LoggingMiddleware that sets trace_id.
+func serveProblem(t *testing.T, handler gin.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("trace_id", "trace-123") })
+	r.Handle(req.Method, req.URL.Path, handler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, w.Body.String())
+	}
+	return w, problem
+}
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+		wantField  string
+	}{
+		{"shipment not found", fmt.Errorf("track: %w", logicv1.ErrShipmentNotFound), 404, "shipment-not-found", ""},
+		{"invalid address", logicv1.ErrInvalidAddress, 400, "invalid-address", ""},
+		{"carrier unavailable", logicv1.ErrCarrierUnavailable, 503, "carrier-unavailable", ""},
+		{"unauthorized", logicv1.ErrUnauthorized, 403, "forbidden", ""},
+		{"status transition", logicv1.ErrInvalidStatusTransition, 409, "invalid-status-transition", ""},
+		{
+			"field error",
+			fmt.Errorf("create: %w", &logicv1.FieldError{Field: "estimated_delivery", Message: "bad", Err: logicv1.ErrInvalidShipment}),
+			400, "invalid-shipment", "estimated_delivery",
+		},
+		{"unknown error", errors.New("pq: connection refused"), 500, "internal-error", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, problem := serveProblem(t, func(c *gin.Context) { respondError(c, tt.err) },
+				httptest.NewRequest(http.MethodGet, "/shipping/v1/public/track", nil))
+
+			if w.Code != tt.wantStatus || problem.Status != tt.wantStatus {
+				t.Errorf("status = %d (body %d), want %d", w.Code, problem.Status, tt.wantStatus)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ProblemContentType) {
+				t.Errorf("Content-Type = %q, want %s", ct, ProblemContentType)
+			}
+			if want := problemTypeBase + tt.wantType; problem.Type != want {
+				t.Errorf("type = %q, want %q", problem.Type, want)
+			}
+			if problem.TraceID != "trace-123" {
+				t.Errorf("trace_id = %q, want trace-123", problem.TraceID)
+			}
+			if problem.Instance != "/shipping/v1/public/track" {
+				t.Errorf("instance = %q, want request path", problem.Instance)
+			}
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Error("internal error message leaked to the client")
+			}
+			if tt.wantField != "" && (len(problem.Errors) != 1 || problem.Errors[0].Field != tt.wantField) {
+				t.Errorf("errors = %+v, want one for %q", problem.Errors, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestBindJSONFieldErrors(t *testing.T) {
+	handler := func(c *gin.Context) {
+		var req domain.CreateShipmentRequest
+		if bindJSON(c, &req) {
+			c.Status(http.StatusNoContent)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/shipping/v1/internal/shipments", strings.NewReader(`{"carrier":"ups"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w, problem := serveProblem(t, handler, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if problem.Type != problemTypeBase+"validation-error" {
+		t.Errorf("type = %q, want validation-error", problem.Type)
+	}
+	var fields []string
+	for _, fe := range problem.Errors {
+		fields = append(fields, fe.Field)
+	}
+	slices.Sort(fields)
+	if !slices.Equal(fields, []string{"order_id", "tracking_number"}) {
+		t.Errorf("fields = %v, want JSON names [order_id tracking_number]", fields)
+	}
+}
+
+func TestProblemTypesDocumented(t *testing.T) {
+	var spec struct {
+		Components struct {
+			Schemas struct {
+				Problem struct {
+					Properties struct {
+						Type struct {
+							Enum []string `json:"enum"`
+						} `json:"type"`
+					} `json:"properties"`
+				} `json:"Problem"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatal(err)
+	}
+	documented := spec.Components.Schemas.Problem.Properties.Type.Enum
+
+	kinds := []problemKind{problemValidation, problemInternal, problemUnavailable, problemUnauthenticated,
+		problemIdempotencyInUse, problemIdempotencyMismatch, problemRequestTooLarge}
+	for _, k := range problemKinds {
+		kinds = append(kinds, k.kind)
+	}
+	for _, k := range kinds {
+		if !slices.Contains(documented, problemTypeBase+k.slug) {
+			t.Errorf("problem type %q is missing from the Problem schema in openapi.json", k.slug)
+		}
+	}
+}
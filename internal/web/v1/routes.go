@@ -0,0 +1,88 @@
+package v1
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/duynhne/shipping-service/internal/auth"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// openAPISpec documents every route registered by RegisterRoutes, with the
+// query parameters its handler reads. Keep it in sync when adding or changing
+// routes; TestOpenAPICoversRoutes enforces it. /health, /ready and /metrics
+// are registered in main, outside the v1 API, and are left out.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Handlers groups the v1 route handlers.
+type Handlers struct {
+	Shipping     *Handler
+	Exception    *ExceptionHandler
+	Subscription *SubscriptionHandler
+	Webhook      *WebhookHandler
+	Stream       *StreamHandler
+	LogLevel     *LogLevelHandler
+
+	// Auth guards the internal routes; nil disables authentication (AUTH_ENABLED=false).
+	Auth *auth.Authenticator
+	// Idempotency handles Idempotency-Key on mutating internal routes; nil disables it.
+	Idempotency *Idempotency
+}
+
+// RegisterRoutes mounts the shipping v1 API — Variant A edge naming (see api-naming-convention.md).
+func RegisterRoutes(r gin.IRouter, h Handlers) {
+	r.GET("/shipping/v1/openapi.json", OpenAPI)
+
+	// Public: customer-facing tracking + estimation (no auth required)
+	r.GET("/shipping/v1/public/track", h.Shipping.TrackShipment)
+	r.GET("/shipping/v1/public/track/stream", h.Stream.TrackStream)
+	r.GET("/shipping/v1/public/estimate", h.Shipping.EstimateShipping)
+	r.POST("/shipping/v1/public/track/subscribe", h.Subscription.Subscribe)
+	r.GET("/shipping/v1/public/track/unsubscribe", h.Subscription.Unsubscribe)
+	r.POST("/shipping/v1/public/track/unsubscribe", h.Subscription.Unsubscribe)
+
+	// Internal: not on gateway. Callers authenticate with a JWT or service API key
+	// and need the scope named per route.
+	read := requireScope(h.Auth, auth.ScopeShipmentsRead)
+	write := requireScope(h.Auth, auth.ScopeShipmentsWrite)
+	manageWebhooks := requireScope(h.Auth, auth.ScopeWebhooksManage)
+	manageLogging := requireScope(h.Auth, auth.ScopeLoggingManage)
+	internal := r.Group("/shipping/v1/internal")
+	// Only internal callers may pin their reads to the primary; public traffic
+	// always reads from the replicas.
+	internal.Use(middleware.ReadConsistencyMiddleware())
+	// Mutating routes replay the stored response for a repeated Idempotency-Key.
+	once := idempotent(h.Idempotency)
+
+	// Internal: called by order-service for order-detail aggregation.
+	internal.GET("/orders/:orderId", read, h.Shipping.GetShipmentByOrder)
+	internal.POST("/orders/:orderId/tracking-token", read, h.Shipping.IssueCustomerToken)
+
+	// Internal: shipment lifecycle (order-service + carrier integrations).
+	internal.POST("/shipments", write, once, h.Shipping.CreateShipment)
+	internal.PUT("/shipments/:shipmentId/status", write, once, h.Shipping.UpdateShipmentStatus)
+
+	// Internal: delivery exception workflow (carrier integrations + ops tooling).
+	internal.POST("/shipments/:shipmentId/exceptions", write, once, h.Exception.RaiseException)
+	internal.GET("/exceptions", read, h.Exception.ListOpenExceptions)
+	internal.POST("/exceptions/:exceptionId/resolve", write, once, h.Exception.ResolveException)
+
+	// Internal: merchant webhook registry + dead-letter replay (ops tooling).
+	internal.POST("/webhooks", manageWebhooks, once, h.Webhook.CreateSubscription)
+	internal.GET("/webhooks", manageWebhooks, h.Webhook.ListSubscriptions)
+	internal.DELETE("/webhooks/:subscriptionId", manageWebhooks, h.Webhook.DeleteSubscription)
+	internal.GET("/webhooks/dead-letters", manageWebhooks, h.Webhook.ListDeadLetters)
+	internal.POST("/webhooks/dead-letters/:deadLetterId/replay", manageWebhooks, once, h.Webhook.ReplayDeadLetter)
+
+	// Internal: runtime log level of the serving replica (ops tooling).
+	internal.GET("/log-level", manageLogging, h.LogLevel.GetLogLevel)
+	internal.PUT("/log-level", manageLogging, h.LogLevel.SetLogLevel)
+}
+
+// OpenAPI handles GET /shipping/v1/openapi.json
+func OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/internal/stream"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// StreamHandler serves live tracking updates over Server-Sent Events.
+type StreamHandler struct {
+	service   *logicv1.ShippingService
+	ownership *logicv1.OwnershipVerifier // nil when tracking verification is disabled
+	hub       *stream.Hub
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(
+	service *logicv1.ShippingService, ownership *logicv1.OwnershipVerifier, hub *stream.Hub, heartbeat time.Duration,
+) *StreamHandler {
+	return &StreamHandler{
+		service:   service,
+		ownership: ownership,
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// TrackStream handles GET /shipping/v1/public/track/stream
+// Query params: tracking_number, optional postal_code / customer_token (as for /track)
+//
+// The first "shipment" event carries the current state; each later
+// "tracking_event" carries one status change or exception event. A comment
+// line is sent every heartbeat interval to keep proxies from closing the
+// connection. The stream ends when the client disconnects or the service
+// shuts down; EventSource clients reconnect on their own. Without ownership
+// proof the snapshot and events are status-only and exception events are skipped.
+func (h *StreamHandler) TrackStream(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	trackingNumber := c.Query("tracking_number")
+	if trackingNumber == "" {
+		respondValidation(c, "Missing required query parameter", FieldError{Field: "tracking_number", Message: "is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("tracking.id", trackingNumber))
+
+	// Subscribe before reading the snapshot so no change falls between the two.
+	sub, ok := h.hub.Subscribe(trackingNumber)
+	if !ok {
+		respondUnavailable(c, "Service is shutting down")
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	shipment, err := h.service.TrackShipment(ctx, trackingNumber)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to track shipment", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+	full, err := h.ownership.Authorize(shipment, ownershipProof(c))
+	if err != nil {
+		zapLogger.Warn("Tracking ownership proof rejected", zap.String("tracking_id", trackingNumber))
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+	c.Status(http.StatusOK)
+
+	if full {
+		c.SSEvent("shipment", shipment)
+	} else {
+		c.SSEvent("shipment", logicv1.StatusOnly(shipment))
+	}
+	c.Writer.Flush()
+
+	zapLogger.Info("Tracking stream opened", zap.String("tracking_id", trackingNumber))
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	sent := 0
+	for {
+		select {
+		case <-ctx.Done():
+			zapLogger.Info("Tracking stream closed by client",
+				zap.String("tracking_id", trackingNumber), zap.Int("events_sent", sent))
+			return
+		case event, open := <-sub.Events:
+			if !open {
+				// Hub closed (shutdown) or this client fell behind.
+				zapLogger.Info("Tracking stream closed by server",
+					zap.String("tracking_id", trackingNumber), zap.Int("events_sent", sent))
+				return
+			}
+			if full {
+				c.SSEvent("tracking_event", event)
+			} else if view, ok := logicv1.StatusOnlyEvent(event); ok {
+				c.SSEvent("tracking_event", view)
+			} else {
+				continue
+			}
+			sent++
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/internal/stream"
+	"github.com/gin-gonic/gin"
+)
+
+// newStreamServer serves the tracking stream for shipment TRK1 (postal code
+// 10115) over a real HTTP server, so client disconnects reach the handler.
+func newStreamServer(t *testing.T, verifier *logicv1.OwnershipVerifier, heartbeat time.Duration) (*httptest.Server, *stream.Hub) {
+	t.Helper()
+	repo := &versionedRepo{shipment: domain.Shipment{
+		ID: 1, OrderID: 7, TrackingNumber: "TRK1", Carrier: "UPS", Status: domain.StatusInTransit,
+		DestinationPostalCode: "10115", Version: 3,
+	}}
+	hub := stream.NewHub()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, Handlers{
+		Stream: NewStreamHandler(logicv1.NewShippingService(repo), verifier, hub, heartbeat),
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	t.Cleanup(hub.Close) // before srv.Close, so open streams end
+	return srv, hub
+}
+
+type sseFrame struct {
+	event   string
+	data    string
+	comment string
+}
+
+// openStream starts a stream request and returns a reader of its frames.
+func openStream(ctx context.Context, t *testing.T, url string) (*http.Response, func() (sseFrame, error)) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	reader := bufio.NewReader(resp.Body)
+	next := func() (sseFrame, error) {
+		var frame sseFrame
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return frame, err
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				if frame != (sseFrame{}) {
+					return frame, nil
+				}
+			case strings.HasPrefix(line, ":"):
+				frame.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "event:"):
+				frame.event = line[len("event:"):]
+			case strings.HasPrefix(line, "data:"):
+				frame.data = line[len("data:"):]
+			}
+		}
+	}
+	return resp, next
+}
+
+// waitForSubscribers waits until hub has n subscribers.
+func waitForSubscribers(t *testing.T, hub *stream.Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.Len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("hub has %d subscribers, want %d", hub.Len(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTrackStreamViews(t *testing.T) {
+	verifier := logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour)
+	tests := []struct {
+		name            string
+		query           string
+		wantFull        bool
+		wantDescription string
+	}{
+		{"without proof", "tracking_number=TRK1", false, ""},
+		{"with postal code", "tracking_number=TRK1&postal_code=10115", true, "Left at the front door"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hub := newStreamServer(t, verifier, time.Hour)
+			resp, next := openStream(context.Background(), t, srv.URL+"/shipping/v1/public/track/stream?"+tt.query)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+				t.Errorf("Content-Type = %q, want text/event-stream", ct)
+			}
+
+			frame, err := next()
+			if err != nil {
+				t.Fatalf("read snapshot: %v", err)
+			}
+			var snapshot map[string]any
+			if err := json.Unmarshal([]byte(frame.data), &snapshot); err != nil {
+				t.Fatalf("snapshot data %q: %v", frame.data, err)
+			}
+			if frame.event != "shipment" || snapshot["status"] != domain.StatusInTransit {
+				t.Errorf("snapshot = %s %s", frame.event, frame.data)
+			}
+			if _, full := snapshot["order_id"]; full != tt.wantFull {
+				t.Errorf("snapshot has full details = %v, want %v: %s", full, tt.wantFull, frame.data)
+			}
+
+			hub.Publish(domain.TrackingEvent{
+				ID: 9, ShipmentID: 1, TrackingNumber: "TRK1", EventType: domain.EventStatusChanged,
+				Status: domain.StatusDelivered, Description: "Left at the front door",
+			})
+			frame, err = next()
+			if err != nil {
+				t.Fatalf("read tracking event: %v", err)
+			}
+			var event domain.TrackingEvent
+			if err := json.Unmarshal([]byte(frame.data), &event); err != nil {
+				t.Fatalf("event data %q: %v", frame.data, err)
+			}
+			if frame.event != "tracking_event" || event.Status != domain.StatusDelivered {
+				t.Errorf("event = %s %s", frame.event, frame.data)
+			}
+			if event.Description != tt.wantDescription {
+				t.Errorf("description = %q, want %q", event.Description, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestTrackStreamWithoutProofHidesEventDetails(t *testing.T) {
+	srv, hub := newStreamServer(t, logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour), time.Hour)
+	_, next := openStream(context.Background(), t, srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1")
+	if frame, err := next(); err != nil || frame.event != "shipment" {
+		t.Fatalf("snapshot = %+v, %v", frame, err)
+	}
+
+	for _, eventType := range []string{domain.EventExceptionOpened, domain.EventExceptionResolved} {
+		hub.Publish(domain.TrackingEvent{
+			ID: 8, ShipmentID: 1, TrackingNumber: "TRK1", EventType: eventType,
+			Status: domain.StatusInTransit, Description: domain.ExceptionDamaged, OccurredAt: "2026-01-02T10:00:00Z",
+		})
+	}
+	hub.Publish(domain.TrackingEvent{
+		ID: 9, ShipmentID: 1, TrackingNumber: "TRK1", EventType: domain.EventStatusChanged,
+		Status: domain.StatusDelivered, Description: "Left at the front door", OccurredAt: "2026-01-02T11:00:00Z",
+	})
+
+	frame, err := next()
+	if err != nil {
+		t.Fatalf("read tracking event: %v", err)
+	}
+	var event map[string]any
+	if err := json.Unmarshal([]byte(frame.data), &event); err != nil {
+		t.Fatalf("event data %q: %v", frame.data, err)
+	}
+	want := map[string]any{
+		"tracking_number": "TRK1", "status": domain.StatusDelivered, "occurred_at": "2026-01-02T11:00:00Z",
+	}
+	if frame.event != "tracking_event" || len(event) != len(want) {
+		t.Fatalf("event = %s %s, want only %v", frame.event, frame.data, want)
+	}
+	for key, value := range want {
+		if event[key] != value {
+			t.Errorf("%s = %v, want %v", key, event[key], value)
+		}
+	}
+}
+
+func TestTrackStreamWrongProof(t *testing.T) {
+	srv, hub := newStreamServer(t, logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour), time.Hour)
+	resp, _ := openStream(context.Background(), t,
+		srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1&postal_code=99999")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.StatusCode)
+	}
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestTrackStreamHeartbeat(t *testing.T) {
+	srv, _ := newStreamServer(t, nil, 10*time.Millisecond)
+	_, next := openStream(context.Background(), t, srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1")
+
+	if frame, err := next(); err != nil || frame.event != "shipment" {
+		t.Fatalf("snapshot = %+v, %v", frame, err)
+	}
+	for range 2 {
+		frame, err := next()
+		if err != nil {
+			t.Fatalf("read heartbeat: %v", err)
+		}
+		if frame.comment != "heartbeat" {
+			t.Errorf("frame = %+v, want a heartbeat comment", frame)
+		}
+	}
+}
+
+func TestTrackStreamClosedByClient(t *testing.T) {
+	srv, hub := newStreamServer(t, nil, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, next := openStream(ctx, t, srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1")
+	if _, err := next(); err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	waitForSubscribers(t, hub, 1)
+
+	cancel()
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestTrackStreamClosedByHub(t *testing.T) {
+	srv, hub := newStreamServer(t, nil, time.Hour)
+	_, next := openStream(context.Background(), t, srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1")
+	if _, err := next(); err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+
+	hub.Close()
+	done := make(chan error, 1)
+	go func() {
+		_, err := next()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("read after hub.Close: err = %v, want EOF", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream still open after hub.Close")
+	}
+
+	resp, _ := openStream(context.Background(), t, srv.URL+"/shipping/v1/public/track/stream?tracking_number=TRK1")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("new stream after hub.Close: status = %d, want 503", resp.StatusCode)
+	}
+}
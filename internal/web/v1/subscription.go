@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type SubscriptionHandler struct {
+	service   *logicv1.SubscriptionService
+	shipping  *logicv1.ShippingService
+	ownership *logicv1.OwnershipVerifier // nil when tracking verification is disabled
+}
+
+func NewSubscriptionHandler(
+	service *logicv1.SubscriptionService, shipping *logicv1.ShippingService, ownership *logicv1.OwnershipVerifier,
+) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		service:   service,
+		shipping:  shipping,
+		ownership: ownership,
+	}
+}
+
+// Subscribe handles POST /shipping/v1/public/track/subscribe
+// Body: {"tracking_number": "...", "email": "..."} or {"tracking_number": "...", "webhook_url": "..."},
+// plus "postal_code" or an X-Customer-Token header proving the shipment is the caller's.
+// A new subscription is returned with 201 and its unsubscribe token; an existing
+// one with 200 and without the token.
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	var req domain.SubscribeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	shipment, err := h.shipping.TrackShipment(ctx, req.TrackingNumber)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to look up shipment to subscribe", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+	// Notifications carry full shipment details, so the status-only view is not enough.
+	proof := logicv1.OwnershipProof{PostalCode: req.PostalCode, Token: c.GetHeader(CustomerTokenHeader)}
+	full, err := h.ownership.Authorize(shipment, proof)
+	if err == nil && !full {
+		err = fmt.Errorf("subscribe to %q without ownership proof: %w", req.TrackingNumber, logicv1.ErrUnauthorized)
+	}
+	if err != nil {
+		zapLogger.Warn("Subscription ownership proof rejected", zap.String("tracking_id", req.TrackingNumber))
+		respondError(c, err)
+		return
+	}
+
+	subscription, created, err := h.service.Subscribe(ctx, &req)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to subscribe to tracking updates", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Tracking subscription created",
+		zap.Int("subscription_id", subscription.ID),
+		zap.Int("shipment_id", subscription.ShipmentID),
+		zap.String("channel", subscription.Channel),
+		zap.Bool("created", created),
+	)
+	if !created {
+		c.JSON(http.StatusOK, subscription)
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// Unsubscribe handles GET and POST /shipping/v1/public/track/unsubscribe?token=...
+// GET is accepted so the link in notification emails works with one click. The
+// token is kept out of access logs (path only), caches and Referer headers.
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	if err := h.service.Unsubscribe(ctx, c.Query("token")); err != nil {
+		span.RecordError(err)
+		zapLogger.Warn("Failed to unsubscribe", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Tracking subscription cancelled")
+	c.JSON(http.StatusOK, gin.H{"status": "unsubscribed"})
+}
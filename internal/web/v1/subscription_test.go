@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// memorySubscriptions keeps active subscriptions by channel and target, and
+// like the Postgres repository returns an existing one without its token.
+type memorySubscriptions struct {
+	domain.SubscriptionRepository
+	active map[string]domain.TrackingSubscription
+}
+
+func (r *memorySubscriptions) Create(
+	_ context.Context, trackingNumber, channel, target, token string,
+) (*domain.TrackingSubscription, bool, error) {
+	key := channel + " " + target
+	if s, ok := r.active[key]; ok {
+		s.UnsubscribeToken = ""
+		return &s, false, nil
+	}
+	s := domain.TrackingSubscription{
+		ID: len(r.active) + 1, ShipmentID: 1, TrackingNumber: trackingNumber,
+		Channel: channel, Target: target, UnsubscribeToken: token,
+	}
+	r.active[key] = s
+	return &s, true, nil
+}
+
+func (r *memorySubscriptions) Unsubscribe(_ context.Context, token string) error {
+	for key, s := range r.active {
+		if s.UnsubscribeToken == token {
+			delete(r.active, key)
+			return nil
+		}
+	}
+	return domain.ErrSubscriptionNotFound
+}
+
+func newSubscriptionRouter(verifier *logicv1.OwnershipVerifier, mw ...gin.HandlerFunc) *gin.Engine {
+	shipments := &versionedRepo{shipment: domain.Shipment{
+		ID: 1, OrderID: 7, TrackingNumber: "TRK1", Status: domain.StatusInTransit,
+		DestinationPostalCode: "10115", Version: 3,
+	}}
+	subscriptions := &memorySubscriptions{active: map[string]domain.TrackingSubscription{}}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(mw...)
+	RegisterRoutes(r, Handlers{Subscription: NewSubscriptionHandler(
+		logicv1.NewSubscriptionService(subscriptions), logicv1.NewShippingService(shipments), verifier,
+	)})
+	return r
+}
+
+func subscribe(r *gin.Engine, body, customerToken string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/shipping/v1/public/track/subscribe", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if customerToken != "" {
+		req.Header.Set(CustomerTokenHeader, customerToken)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubscribeOwnershipProof(t *testing.T) {
+	verifier := logicv1.NewOwnershipVerifier(strings.Repeat("s", 32), time.Hour)
+
+	tests := []struct {
+		name          string
+		verifier      *logicv1.OwnershipVerifier
+		body          string
+		customerToken string
+		wantStatus    int
+	}{
+		{"no proof", verifier, `{"tracking_number":"TRK1","email":"a@example.com"}`, "", http.StatusForbidden},
+		{
+			"wrong postal code", verifier,
+			`{"tracking_number":"TRK1","email":"a@example.com","postal_code":"99999"}`, "", http.StatusForbidden,
+		},
+		{"wrong token", verifier, `{"tracking_number":"TRK1","email":"a@example.com"}`, "1.bad", http.StatusForbidden},
+		{
+			"postal code", verifier,
+			`{"tracking_number":"TRK1","email":"a@example.com","postal_code":"10 115"}`, "", http.StatusCreated,
+		},
+		{
+			"customer token", verifier,
+			`{"tracking_number":"TRK1","email":"a@example.com"}`, verifier.IssueToken(7).Token, http.StatusCreated,
+		},
+		{"verification disabled", nil, `{"tracking_number":"TRK1","email":"a@example.com"}`, "", http.StatusCreated},
+		{"unknown shipment", verifier, `{"tracking_number":"TRK2","email":"a@example.com"}`, "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := subscribe(newSubscriptionRouter(tt.verifier), tt.body, tt.customerToken)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSubscribeTokenOnlyOnCreate(t *testing.T) {
+	r := newSubscriptionRouter(nil)
+	body := `{"tracking_number":"TRK1","email":"a@example.com"}`
+
+	first := subscribe(r, body, "")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, want %d: %s", first.Code, http.StatusCreated, first.Body.String())
+	}
+	var created domain.TrackingSubscription
+	if err := json.Unmarshal(first.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.UnsubscribeToken == "" {
+		t.Error("new subscription has no unsubscribe token")
+	}
+
+	again := subscribe(r, body, "")
+	if again.Code != http.StatusOK {
+		t.Fatalf("repeat status = %d, want %d: %s", again.Code, http.StatusOK, again.Body.String())
+	}
+	if strings.Contains(again.Body.String(), "unsubscribe_token") {
+		t.Errorf("existing subscription leaks its token: %s", again.Body.String())
+	}
+}
+
+func TestUnsubscribeTokenNotLogged(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	r := newSubscriptionRouter(nil, middleware.LoggingMiddleware(zap.New(core)))
+	var created domain.TrackingSubscription
+	if err := json.Unmarshal(subscribe(r, `{"tracking_number":"TRK1","email":"a@example.com"}`, "").Body.Bytes(),
+		&created); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/shipping/v1/public/track/unsubscribe?token="+created.UnsubscribeToken, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w.Header().Get("Referrer-Policy") != "no-referrer" || w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("headers = %v, want no-referrer and no-store", w.Header())
+	}
+	for _, entry := range logs.All() {
+		if line := fmt.Sprint(entry.Message, entry.ContextMap()); strings.Contains(line, created.UnsubscribeToken) {
+			t.Errorf("log leaks the unsubscribe token: %s", line)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	logicv1 "github.com/duynhne/shipping-service/internal/logic/v1"
+	"github.com/duynhne/shipping-service/middleware"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service *logicv1.WebhookService
+}
+
+func NewWebhookHandler(service *logicv1.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// CreateSubscription handles POST /shipping/v1/internal/webhooks
+// Body: {"url": "...", "secret": "...", "event_types": [...], "carrier": "...", "order_id": 1}
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	var req domain.CreateWebhookSubscriptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(ctx, &req)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to create webhook subscription", zap.Error(err))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Webhook subscription created", zap.Int("subscription_id", subscription.ID))
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListSubscriptions handles GET /shipping/v1/internal/webhooks
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	subscriptions, err := h.service.ListSubscriptions(ctx)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// DeleteSubscription handles DELETE /shipping/v1/internal/webhooks/:subscriptionId
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	subscriptionID, err := strconv.Atoi(c.Param("subscriptionId"))
+	if err != nil {
+		respondValidation(c, "Invalid path parameter", FieldError{Field: "subscriptionId", Message: "must be an integer"})
+		return
+	}
+
+	if err := h.service.DeleteSubscription(ctx, subscriptionID); err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to delete webhook subscription", zap.Error(err), zap.Int("subscription_id", subscriptionID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Webhook subscription deleted", zap.Int("subscription_id", subscriptionID))
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeadLetters handles GET /shipping/v1/internal/webhooks/dead-letters
+// Query params: limit (optional, max 100)
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 0 {
+			respondValidation(c, "Invalid query parameter", FieldError{Field: "limit", Message: "must be a non-negative integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	deadLetters, err := h.service.ListDeadLetters(ctx, limit)
+	if err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to list webhook dead letters", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dead_letters": deadLetters})
+}
+
+// ReplayDeadLetter handles POST /shipping/v1/internal/webhooks/dead-letters/:deadLetterId/replay
+func (h *WebhookHandler) ReplayDeadLetter(c *gin.Context) {
+	ctx, span := middleware.StartSpan(c.Request.Context(), "http.request", trace.WithAttributes(
+		attribute.String("layer", "web"),
+		attribute.String("method", c.Request.Method),
+		attribute.String("path", c.Request.URL.Path),
+	))
+	defer span.End()
+
+	zapLogger := middleware.GetLoggerFromGinContext(c)
+
+	deadLetterID, err := strconv.ParseInt(c.Param("deadLetterId"), 10, 64)
+	if err != nil {
+		respondValidation(c, "Invalid path parameter", FieldError{Field: "deadLetterId", Message: "must be an integer"})
+		return
+	}
+
+	if err := h.service.ReplayDeadLetter(ctx, deadLetterID); err != nil {
+		span.RecordError(err)
+		zapLogger.Error("Failed to replay webhook dead letter", zap.Error(err), zap.Int64("dead_letter_id", deadLetterID))
+
+		respondError(c, err)
+		return
+	}
+
+	zapLogger.Info("Webhook dead letter replayed", zap.Int64("dead_letter_id", deadLetterID))
+	c.JSON(http.StatusAccepted, gin.H{"status": "requeued"})
+}
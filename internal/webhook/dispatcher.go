@@ -0,0 +1,72 @@
+// Package webhook pushes shipment events to merchant (B2B) webhook subscriptions.
+//
+// The Dispatcher is an outbox publisher: for each relayed event it queues one
+// delivery per matching subscription in webhook_deliveries. The Worker sends
+// queued deliveries as signed JSON POSTs, retries failures with exponential
+// backoff and moves deliveries that exhaust their retries to the dead-letter
+// table, from where ops can replay them.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/outbox"
+)
+
+// Dispatcher queues webhook deliveries for outbox events. It implements outbox.Publisher.
+type Dispatcher struct {
+	repo domain.WebhookRepository
+}
+
+func NewDispatcher(repo domain.WebhookRepository) *Dispatcher {
+	return &Dispatcher{repo: repo}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	var payload domain.ShipmentEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode event %d payload: %w", event.ID, err)
+	}
+
+	subscriptions, err := d.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+
+	var subscriptionIDs []int
+	for _, sub := range subscriptions {
+		if matches(sub, event.EventType, payload) {
+			subscriptionIDs = append(subscriptionIDs, sub.ID)
+		}
+	}
+	if len(subscriptionIDs) == 0 {
+		return nil
+	}
+
+	// Merchants receive the same envelope as the outbox publishers.
+	body, err := json.Marshal(outbox.NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("marshal event %d: %w", event.ID, err)
+	}
+
+	return d.repo.EnqueueDeliveries(ctx, event, body, subscriptionIDs)
+}
+
+// matches applies a subscription's event filter and carrier/order scope.
+func matches(sub domain.WebhookSubscription, eventType string, payload domain.ShipmentEventPayload) bool {
+	if len(sub.EventTypes) > 0 && !slices.Contains(sub.EventTypes, eventType) {
+		return false
+	}
+	if sub.Carrier != "" && !strings.EqualFold(sub.Carrier, payload.Carrier) {
+		return false
+	}
+	if sub.OrderID != nil && *sub.OrderID != payload.OrderID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+func TestMatches(t *testing.T) {
+	orderID := 2
+	otherOrder := 3
+	payload := domain.ShipmentEventPayload{ShipmentID: 2, OrderID: 2, Carrier: "USPS"}
+
+	tests := []struct {
+		name      string
+		sub       domain.WebhookSubscription
+		eventType string
+		want      bool
+	}{
+		{name: "No filters", sub: domain.WebhookSubscription{}, eventType: domain.OutboxShipmentCreated, want: true},
+		{
+			name:      "Event filter match",
+			sub:       domain.WebhookSubscription{EventTypes: []string{domain.OutboxShipmentStatusChanged}},
+			eventType: domain.OutboxShipmentStatusChanged,
+			want:      true,
+		},
+		{
+			name:      "Event filter miss",
+			sub:       domain.WebhookSubscription{EventTypes: []string{domain.OutboxShipmentStatusChanged}},
+			eventType: domain.OutboxShipmentCreated,
+			want:      false,
+		},
+		{name: "Carrier scope case-insensitive", sub: domain.WebhookSubscription{Carrier: "usps"}, want: true},
+		{name: "Carrier scope miss", sub: domain.WebhookSubscription{Carrier: "UPS"}, want: false},
+		{name: "Order scope match", sub: domain.WebhookSubscription{OrderID: &orderID}, want: true},
+		{name: "Order scope miss", sub: domain.WebhookSubscription{OrderID: &otherOrder}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matches(tt.sub, tt.eventType, payload); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package webhook
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var deliveryAttempts = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "webhook_delivery_attempts_total",
+		Help: "Merchant webhook delivery attempts by result (delivered, retry, dead_letter)",
+	},
+	[]string{"event_type", "result"},
+)
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every webhook delivery.
+const (
+	SignatureHeader = "X-Webhook-Signature" // "v1=<hex HMAC-SHA256>"
+	TimestampHeader = "X-Webhook-Timestamp" // Unix seconds, part of the signed content
+	EventIDHeader   = "X-Webhook-Event-Id"  // Outbox event ID, stable across retries (dedupe key)
+	EventTypeHeader = "X-Webhook-Event-Type"
+	AttemptHeader   = "X-Webhook-Attempt"
+)
+
+const signatureVersion = "v1="
+
+// Sign returns the signature header value for a payload: HMAC-SHA256 over
+// "<timestamp>.<body>" keyed with the subscription secret. Including the
+// timestamp lets receivers reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header against the payload and rejects timestamps
+// further than tolerance from now. It is what a receiver would run, exported
+// for partner SDK examples and tests.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	if !strings.HasPrefix(signature, signatureVersion) {
+		return false
+	}
+	expected := Sign(secret, ts, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":1,"type":"shipment.status_changed"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign("s3cret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		want      bool
+	}{
+		{name: "Valid", secret: "s3cret", signature: sig, timestamp: ts, body: body, now: now, want: true},
+		{name: "Wrong secret", secret: "other", signature: sig, timestamp: ts, body: body, now: now, want: false},
+		{name: "Tampered body", secret: "s3cret", signature: sig, timestamp: ts, body: []byte(`{}`), now: now, want: false},
+		{name: "Timestamp not signed", secret: "s3cret", signature: sig, timestamp: "1700000001", body: body, now: now, want: false},
+		{name: "Stale", secret: "s3cret", signature: sig, timestamp: ts, body: body, now: now.Add(10 * time.Minute), want: false},
+		{name: "Missing version", secret: "s3cret", signature: sig[3:], timestamp: ts, body: body, now: now, want: false},
+		{name: "Malformed timestamp", secret: "s3cret", signature: sig, timestamp: "abc", body: body, now: now, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, 5*time.Minute, tt.now)
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/outbox"
+	"go.uber.org/zap"
+)
+
+// WorkerConfig tunes the delivery loop.
+type WorkerConfig struct {
+	PollInterval time.Duration  // How often to look for due deliveries
+	BatchSize    int            // Max deliveries claimed per poll
+	Timeout      time.Duration  // HTTP timeout per delivery attempt
+	MaxAttempts  int            // Attempts before a delivery is dead-lettered
+	Backoff      outbox.Backoff // Retry delay policy
+}
+
+// Worker sends queued webhook deliveries.
+type Worker struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	cfg    WorkerConfig
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(repo domain.WebhookRepository, cfg WorkerConfig, logger *zap.Logger) *Worker {
+	return &Worker{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Start runs the delivery loop in a background goroutine until Stop is called.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Go(func() { w.run(ctx) })
+}
+
+// Stop cancels the delivery loop and waits for the in-flight batch to finish.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if w.deliverBatch(ctx) == w.cfg.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch claims due deliveries and sends them concurrently, so one slow
+// merchant endpoint does not hold up the rest of the batch.
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	// The lease covers every attempt in the batch, plus headroom for marking.
+	lease := w.cfg.Timeout + 30*time.Second
+	deliveries, err := w.repo.ClaimDeliveries(ctx, w.cfg.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Go(func() { w.deliver(ctx, delivery) })
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery domain.WebhookDelivery) {
+	err := w.send(ctx, delivery)
+
+	markCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	log := w.logger.With(
+		zap.Int64("delivery_id", delivery.ID),
+		zap.Int("subscription_id", delivery.SubscriptionID),
+		zap.Int64("event_id", delivery.EventID),
+		zap.Int("attempt", delivery.Attempts+1),
+	)
+
+	switch {
+	case err == nil:
+		deliveryAttempts.WithLabelValues(delivery.EventType, "delivered").Inc()
+		if markErr := w.repo.MarkDeliveryDelivered(markCtx, delivery.ID); markErr != nil {
+			log.Error("Failed to mark webhook delivered", zap.Error(markErr))
+		}
+	case delivery.Attempts+1 >= w.cfg.MaxAttempts:
+		deliveryAttempts.WithLabelValues(delivery.EventType, "dead_letter").Inc()
+		log.Warn("Webhook delivery exhausted retries, dead-lettering", zap.Error(err))
+		if markErr := w.repo.DeadLetterDelivery(markCtx, delivery.ID, err.Error()); markErr != nil {
+			log.Error("Failed to dead-letter webhook delivery", zap.Error(markErr))
+		}
+	default:
+		deliveryAttempts.WithLabelValues(delivery.EventType, "retry").Inc()
+		retryIn := w.cfg.Backoff.Delay(delivery.Attempts)
+		log.Info("Webhook delivery failed, scheduling retry", zap.Duration("retry_in", retryIn), zap.Error(err))
+		if markErr := w.repo.MarkDeliveryFailed(markCtx, delivery.ID, retryIn, err.Error()); markErr != nil {
+			log.Error("Failed to record webhook failure", zap.Error(markErr))
+		}
+	}
+}
+
+// send POSTs the signed payload. Any 2xx response counts as delivered.
+func (w *Worker) send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+	req.Header.Set(EventIDHeader, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(AttemptHeader, strconv.Itoa(delivery.Attempts+1))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/duynhne/shipping-service/internal/core/domain"
+	"github.com/duynhne/shipping-service/internal/outbox"
+	"go.uber.org/zap"
+)
+
+// memoryQueue is an in-memory delivery queue with the semantics of the
+// Postgres repository: every claim returns the pending deliveries, and
+// dead-lettering moves a delivery out of the queue until it is replayed.
+type memoryQueue struct {
+	domain.WebhookRepository
+
+	mu          sync.Mutex
+	pending     map[int64]*domain.WebhookDelivery
+	delivered   []int64
+	retries     []time.Duration
+	deadLetters map[int64]string // delivery ID → last error
+	parked      map[int64]*domain.WebhookDelivery
+}
+
+func newMemoryQueue(deliveries ...domain.WebhookDelivery) *memoryQueue {
+	q := &memoryQueue{
+		pending:     map[int64]*domain.WebhookDelivery{},
+		deadLetters: map[int64]string{},
+		parked:      map[int64]*domain.WebhookDelivery{},
+	}
+	for _, d := range deliveries {
+		q.pending[d.ID] = &d
+	}
+	return q
+}
+
+func (q *memoryQueue) ClaimDeliveries(_ context.Context, limit int, _ time.Duration) ([]domain.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []domain.WebhookDelivery
+	for _, d := range q.pending {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, *d)
+	}
+	return out, nil
+}
+
+func (q *memoryQueue) MarkDeliveryDelivered(_ context.Context, id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, id)
+	q.delivered = append(q.delivered, id)
+	return nil
+}
+
+func (q *memoryQueue) MarkDeliveryFailed(_ context.Context, id int64, retryIn time.Duration, _ string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending[id].Attempts++
+	q.retries = append(q.retries, retryIn)
+	return nil
+}
+
+func (q *memoryQueue) DeadLetterDelivery(_ context.Context, id int64, lastError string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d := q.pending[id]
+	d.Attempts++
+	delete(q.pending, id)
+	q.parked[id] = d
+	q.deadLetters[id] = lastError
+	return nil
+}
+
+// replay re-queues a dead-lettered delivery with a fresh retry budget, like
+// WebhookRepository.ReplayDeadLetter.
+func (q *memoryQueue) replay(id int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d := q.parked[id]
+	d.Attempts = 0
+	delete(q.parked, id)
+	delete(q.deadLetters, id)
+	q.pending[id] = d
+}
+
+// flakyEndpoint fails the first failures requests with 500, then accepts,
+// recording each request's attempt header and whether its signature verified.
+type flakyEndpoint struct {
+	failures int
+	secret   string
+
+	requests atomic.Int32
+	mu       sync.Mutex
+	attempts []int
+	verified []bool
+}
+
+func (e *flakyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	attempt, _ := strconv.Atoi(r.Header.Get(AttemptHeader))
+	ok := Verify(e.secret, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, time.Minute, time.Now())
+
+	e.mu.Lock()
+	e.attempts = append(e.attempts, attempt)
+	e.verified = append(e.verified, ok)
+	e.mu.Unlock()
+
+	if int(e.requests.Add(1)) <= e.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestWorker(q *memoryQueue, maxAttempts int) *Worker {
+	return NewWorker(q, WorkerConfig{
+		PollInterval: time.Hour,
+		BatchSize:    10,
+		Timeout:      time.Second,
+		MaxAttempts:  maxAttempts,
+		Backoff:      outbox.Backoff{Initial: time.Second, Max: 4 * time.Second},
+	}, zap.NewNop())
+}
+
+func testDelivery(url string) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID: 1, SubscriptionID: 5, URL: url, Secret: "whsec_test", EventID: 42,
+		EventType: domain.OutboxShipmentStatusChanged, Payload: []byte(`{"shipment_id":1,"status":"delivered"}`),
+	}
+}
+
+func TestWorkerRetriesWithBackoffUntilDelivered(t *testing.T) {
+	endpoint := &flakyEndpoint{failures: 2, secret: "whsec_test"}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	q := newMemoryQueue(testDelivery(srv.URL))
+	w := newTestWorker(q, 5)
+	for range 3 {
+		w.deliverBatch(context.Background())
+	}
+
+	if len(q.delivered) != 1 || len(q.pending) != 0 {
+		t.Fatalf("delivered = %v, pending = %d, want the delivery sent", q.delivered, len(q.pending))
+	}
+	// Full jitter: retry n waits at most min(Max, Initial*2^n).
+	ceilings := []time.Duration{time.Second, 2 * time.Second}
+	if len(q.retries) != len(ceilings) {
+		t.Fatalf("retries = %v, want %d", q.retries, len(ceilings))
+	}
+	for i, delay := range q.retries {
+		if delay < 0 || delay > ceilings[i] {
+			t.Errorf("retry %d delay = %v, want within [0, %v]", i, delay, ceilings[i])
+		}
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(endpoint.attempts, want) {
+		t.Errorf("%s = %v, want %v", AttemptHeader, endpoint.attempts, want)
+	}
+	for i, ok := range endpoint.verified {
+		if !ok {
+			t.Errorf("attempt %d: signature did not verify", i+1)
+		}
+	}
+}
+
+func TestWorkerDeadLettersAfterMaxAttempts(t *testing.T) {
+	endpoint := &flakyEndpoint{failures: 100, secret: "whsec_test"}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	q := newMemoryQueue(testDelivery(srv.URL))
+	w := newTestWorker(q, 3)
+	for range 4 {
+		w.deliverBatch(context.Background())
+	}
+
+	if got := endpoint.requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 (MaxAttempts)", got)
+	}
+	if len(q.retries) != 2 {
+		t.Errorf("retries = %v, want 2 before the dead letter", q.retries)
+	}
+	if lastError, ok := q.deadLetters[1]; !ok || lastError != "unexpected status 500" {
+		t.Errorf("dead letter = %q, %v, want the last error recorded", lastError, ok)
+	}
+	if len(q.pending) != 0 {
+		t.Errorf("pending = %d, want the dead letter out of the queue", len(q.pending))
+	}
+}
+
+func TestWorkerSendsReplayedDeadLetter(t *testing.T) {
+	endpoint := &flakyEndpoint{failures: 2, secret: "whsec_test"}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	q := newMemoryQueue(testDelivery(srv.URL))
+	w := newTestWorker(q, 2)
+	for range 2 {
+		w.deliverBatch(context.Background())
+	}
+	if _, ok := q.deadLetters[1]; !ok {
+		t.Fatal("delivery not dead-lettered")
+	}
+
+	// The merchant fixed their endpoint; ops replays the dead letter.
+	q.replay(1)
+	w.deliverBatch(context.Background())
+
+	if len(q.delivered) != 1 {
+		t.Fatalf("delivered = %v, want the replayed delivery sent", q.delivered)
+	}
+	if want := []int{1, 2, 1}; !slices.Equal(endpoint.attempts, want) {
+		t.Errorf("%s = %v, want %v (replay starts a fresh retry budget)", AttemptHeader, endpoint.attempts, want)
+	}
+}
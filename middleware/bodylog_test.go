@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBodyLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		wantLogged  bool
+		wantFields  map[string]any
+	}{
+		{
+			name:        "redacts configured route",
+			path:        "/shipments/1",
+			contentType: "application/json",
+			body:        `{"secret":"s","email":"a@b.co"}`,
+			wantLogged:  true,
+			wantFields: map[string]any{
+				"request_body":  `{"email":"[EMAIL]","secret":"[REDACTED]"}`,
+				"response_body": `{"secret":"[REDACTED]"}`,
+			},
+		},
+		{
+			name:        "skips other routes",
+			path:        "/other",
+			contentType: "application/json",
+			body:        `{}`,
+		},
+		{
+			name:        "flags bodies over the cap",
+			path:        "/shipments/1",
+			contentType: "application/json",
+			body:        `{"description":"` + strings.Repeat("x", 64) + `"}`,
+			wantLogged:  true,
+			wantFields:  map[string]any{"request_body_truncated": true},
+		},
+		{
+			name:        "omits unlisted content types",
+			path:        "/shipments/1",
+			contentType: "application/octet-stream",
+			body:        "raw",
+			wantLogged:  true,
+			wantFields:  map[string]any{"response_body": `{"secret":"[REDACTED]"}`},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			var handlerBody string
+
+			r := gin.New()
+			r.Use(func(c *gin.Context) { c.Set("logger", zap.New(core)) })
+			r.Use(BodyLoggingMiddleware(BodyLogConfig{
+				Routes:       []string{"/shipments/:id"},
+				MaxBytes:     48,
+				ContentTypes: []string{"application/json"},
+				Redactor:     NewRedactor([]string{"secret"}),
+			}))
+			handler := func(c *gin.Context) {
+				b, _ := io.ReadAll(c.Request.Body)
+				handlerBody = string(b)
+				c.JSON(http.StatusOK, gin.H{"secret": "s"})
+			}
+			r.POST("/shipments/:id", handler)
+			r.POST("/other", handler)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if handlerBody != tt.body {
+				t.Errorf("handler read %q, want %q", handlerBody, tt.body)
+			}
+			entries := logs.FilterMessage("HTTP payload").All()
+			if (len(entries) == 1) != tt.wantLogged {
+				t.Fatalf("logged %d payload entries, want logged = %v", len(entries), tt.wantLogged)
+			}
+			if !tt.wantLogged {
+				return
+			}
+			fields := entries[0].ContextMap()
+			for k, want := range tt.wantFields {
+				if fields[k] != want {
+					t.Errorf("%s = %v, want %v", k, fields[k], want)
+				}
+			}
+			if _, ok := fields["request_body"]; ok && tt.wantFields["request_body"] == nil {
+				t.Errorf("request_body logged unexpectedly: %v", fields["request_body"])
+			}
+		})
+	}
+}
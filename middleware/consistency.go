@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ReadConsistencyHeader lets a caller that has just written read its own
+// writes: "X-Read-Consistency: primary" sends the request's reads to the
+// primary instead of a possibly lagging read replica. The same key is
+// honored in gRPC metadata.
+const ReadConsistencyHeader = "X-Read-Consistency"
+
+// readPrimary is the ReadConsistencyHeader value that pins reads to the primary.
+const readPrimary = "primary"
+
+// ReadConsistencyMiddleware honors ReadConsistencyHeader on HTTP requests.
+func ReadConsistencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.EqualFold(c.GetHeader(ReadConsistencyHeader), readPrimary) {
+			c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// GRPCReadConsistencyInterceptor honors ReadConsistencyHeader in the request
+// metadata of unary calls to the given full method names. Like the HTTP public
+// routes, other methods ignore it, so anonymous callers cannot move load onto
+// the primary.
+func GRPCReadConsistencyInterceptor(methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, v := range md.Get(ReadConsistencyHeader) {
+				if strings.EqualFold(v, readPrimary) {
+					ctx = database.WithPrimary(ctx)
+					break
+				}
+			}
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	database "github.com/duynhne/shipping-service/internal/core"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestReadConsistencyMiddleware(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"primary", true},
+		{"PRIMARY", true},
+		{"replica", false},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var got bool
+			r := gin.New()
+			r.Use(ReadConsistencyMiddleware())
+			r.GET("/", func(c *gin.Context) { got = database.PrimaryRequested(c.Request.Context()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(ReadConsistencyHeader, tt.header)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("PrimaryRequested = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCReadConsistencyInterceptor(t *testing.T) {
+	const internal, public = "/test.Service/Internal", "/test.Service/Public"
+	tests := []struct {
+		name   string
+		method string
+		value  string
+		want   bool
+	}{
+		{"internal without metadata", internal, "", false},
+		{"internal primary", internal, "primary", true},
+		{"internal replica", internal, "replica", false},
+		{"public primary", public, "primary", false},
+	}
+
+	interceptor := GRPCReadConsistencyInterceptor(internal)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.value != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ReadConsistencyHeader, tt.value))
+			}
+
+			var got bool
+			handler := func(ctx context.Context, _ any) (any, error) {
+				got = database.PrimaryRequested(ctx)
+				return nil, nil
+			}
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			if got != tt.want {
+				t.Errorf("PrimaryRequested = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
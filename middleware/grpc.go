@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcHTTPStatus maps gRPC codes to the HTTP status used in the "code" label, so
+// gRPC calls land in the same RED histogram and error-rate alerts (code=~"5..")
+// as HTTP requests. Mapping follows grpc-gateway.
+var grpcHTTPStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// GRPCTracingInterceptor starts a server span per unary call, continuing the
+// W3C trace context sent in the request metadata.
+//
+// Usage:
+//
+//	grpc.NewServer(grpc.ChainUnaryInterceptor(
+//	    middleware.GRPCTracingInterceptor(),
+//	    middleware.GRPCPrometheusInterceptor(),
+//	))
+func GRPCTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		}
+
+		service, method := path.Split(info.FullMethod)
+		ctx, span := GetTracer().Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.service", path.Clean(service)[1:]),
+				attribute.String("rpc.method", method),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if grpcHTTPStatus[code] >= http.StatusInternalServerError {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+
+		return resp, err
+	}
+}
+
+// GRPCPrometheusInterceptor records unary calls in the same RED metrics as
+// PrometheusMiddleware, with method "GRPC" and the full gRPC method as path.
+func GRPCPrometheusInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const method = "GRPC"
+		start := time.Now()
+
+		requestsInFlight.WithLabelValues(method, info.FullMethod).Inc()
+		defer requestsInFlight.WithLabelValues(method, info.FullMethod).Dec()
+
+		resp, err := handler(ctx, req)
+
+		duration := time.Since(start).Seconds()
+		statusCode := strconv.Itoa(grpcHTTPStatus[status.Code(err)])
+
+		span := trace.SpanFromContext(ctx)
+		if span.SpanContext().HasTraceID() {
+			requestDuration.WithLabelValues(method, info.FullMethod, statusCode).(prometheus.ExemplarObserver).ObserveWithExemplar(
+				duration, prometheus.Labels{"traceID": span.SpanContext().TraceID().String()},
+			)
+		} else {
+			requestDuration.WithLabelValues(method, info.FullMethod, statusCode).Observe(duration)
+		}
+		recordRequestDuration(ctx, method, info.FullMethod, statusCode, duration)
+
+		return resp, err
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -1,11 +1,18 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
-	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/duynhne/shipping-service/config"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -13,54 +20,59 @@ import (
 const TraceIDHeader = "X-Trace-ID"
 const TraceParentHeader = "traceparent"
 
-// GetTraceID extracts trace-id from request headers or generates a new one
+// GetTraceID returns the request's trace ID: the one of the span started by
+// TracingMiddleware, else a valid incoming traceparent or X-Trace-ID, else a
+// new random one.
 func GetTraceID(c *gin.Context) string {
-	// Try W3C Trace Context first (traceparent header)
-	if traceParent := c.GetHeader(TraceParentHeader); traceParent != "" {
-		// traceparent format: version-trace_id-parent_id-flags
-		// Extract trace_id (second part)
-		parts := splitTraceParent(traceParent)
-		if len(parts) >= 2 && parts[1] != "" {
-			return parts[1]
-		}
-	}
+	return requestSpanContext(c).TraceID().String()
+}
 
-	// Fallback to X-Trace-ID header
-	if traceID := c.GetHeader(TraceIDHeader); traceID != "" {
-		return traceID
+// requestSpanContext returns the span context to log and echo for a request.
+// With tracing enabled it is TracingMiddleware's span, sampled or not, so log
+// lines match the trace in Tempo. Without a span (tracing disabled or a
+// skipped path) it continues a valid traceparent, then X-Trace-ID, under a new
+// span ID; malformed values are ignored.
+func requestSpanContext(c *gin.Context) trace.SpanContext {
+	if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+		return sc
 	}
 
-	// Generate new trace-id if not present
-	return generateTraceID()
+	cfg := trace.SpanContextConfig{SpanID: generateSpanID()}
+	remote := trace.SpanContextFromContext(
+		propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(c.Request.Header)))
+	switch {
+	case remote.IsValid():
+		cfg.TraceID, cfg.TraceFlags, cfg.TraceState = remote.TraceID(), remote.TraceFlags(), remote.TraceState()
+	default:
+		if id, err := trace.TraceIDFromHex(c.GetHeader(TraceIDHeader)); err == nil {
+			cfg.TraceID = id
+		} else {
+			cfg.TraceID = generateTraceID()
+		}
+	}
+	return trace.NewSpanContext(cfg)
 }
 
-// splitTraceParent splits traceparent header value
-func splitTraceParent(traceParent string) []string {
-	// Simple split by hyphen, traceparent format: 00-<trace_id>-<parent_id>-<flags>
-	parts := make([]string, 0, 4)
-	start := 0
-	for i := range len(traceParent) {
-		if traceParent[i] == '-' {
-			if start < i {
-				parts = append(parts, traceParent[start:i])
-			}
-			start = i + 1
+// generateTraceID generates a random trace ID
+func generateTraceID() trace.TraceID {
+	var id trace.TraceID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			id[len(id)-1] = 1
 		}
 	}
-	if start < len(traceParent) {
-		parts = append(parts, traceParent[start:])
-	}
-	return parts
+	return id
 }
 
-// generateTraceID generates a trace-id using random bytes
-func generateTraceID() string {
-	// Generate 16 random bytes (32 hex characters)
-	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
-		return "00000000000000000000000000000000"
+// generateSpanID generates a random span ID
+func generateSpanID() trace.SpanID {
+	var id trace.SpanID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			id[len(id)-1] = 1
+		}
 	}
-	return hex.EncodeToString(b)
+	return id
 }
 
 // LoggingMiddleware creates a Gin middleware for structured logging with trace-id
@@ -70,17 +82,22 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		path := c.Request.URL.Path
 		method := c.Request.Method
 
-		// Get or generate trace-id
-		traceID := GetTraceID(c)
+		// Trace and span IDs of the request, shared with TracingMiddleware's span
+		sc := requestSpanContext(c)
+		traceID := sc.TraceID().String()
+		spanID := sc.SpanID().String()
 
 		// Store trace-id in context for handlers to use
 		c.Set("trace_id", traceID)
+		c.Set("span_id", spanID)
 
 		// Store logger in context for handlers to use
-		loggerWithTrace := logger.With(zap.String("trace_id", traceID))
+		loggerWithTrace := logger.With(zap.String("trace_id", traceID), zap.String("span_id", spanID), spanContextField(sc))
 		c.Set("logger", loggerWithTrace)
 
-		// Add trace-id to response header
+		// Echo the trace context: traceparent for W3C clients, X-Trace-ID for older ones
+		propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), sc),
+			propagation.HeaderCarrier(c.Writer.Header()))
 		c.Header(TraceIDHeader, traceID)
 
 		// Process request
@@ -93,6 +110,8 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		// Log request/response
 		logger.Info("HTTP request",
 			zap.String("trace_id", traceID),
+			zap.String("span_id", spanID),
+			spanContextField(sc),
 			zap.String("method", method),
 			zap.String("path", path),
 			zap.Int("status", statusCode),
@@ -105,6 +124,8 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		if statusCode >= 400 {
 			logger.Error("HTTP error",
 				zap.String("trace_id", traceID),
+				zap.String("span_id", spanID),
+				spanContextField(sc),
 				zap.String("method", method),
 				zap.String("path", path),
 				zap.Int("status", statusCode),
@@ -114,6 +135,21 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// RecoveryMiddleware turns a panic into a 500 and logs it with the path only.
+// gin.Recovery would dump the raw request, query and headers included, which
+// carry customer and unsubscribe tokens.
+func RecoveryMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.Error("Panic recovered",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Any("panic", recovered),
+			zap.Stack("stack"),
+		)
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
+
 // GetLoggerFromContext retrieves logger with trace-id from Gin context
 func GetLoggerFromContext(c *gin.Context, baseLogger *zap.Logger) *zap.Logger {
 	traceID, exists := c.Get("trace_id")
@@ -131,21 +167,52 @@ func GetLoggerFromGinContext(c *gin.Context) *zap.Logger {
 			return l
 		}
 	}
-	// Fallback: create a basic logger
-	logger, _ := NewLogger()
-	return logger
+	// Fallback: the global logger, which main replaces with NewLogger's (a no-op in tests)
+	return zap.L()
 }
 
-// NewLogger creates a new zap logger with JSON encoder for production
-func NewLogger() (*zap.Logger, error) {
+// NewLogger creates the service logger from LOG_* settings. The returned level
+// can be changed at runtime and takes effect immediately.
+func NewLogger(cfg *config.Config) (*zap.Logger, zap.AtomicLevel, error) {
+	level, err := zap.ParseAtomicLevel(strings.ToLower(cfg.Logging.Level))
+	if err != nil {
+		return nil, level, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
 	config := zap.NewProductionConfig()
+	config.Level = level
 	config.EncoderConfig.TimeKey = "timestamp"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.EncoderConfig.MessageKey = "message"
 	config.EncoderConfig.LevelKey = "level"
 	config.EncoderConfig.CallerKey = "caller"
+	if strings.EqualFold(cfg.Logging.Format, "console") {
+		config.Encoding = "console"
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
 
-	return config.Build()
+	config.Sampling = nil
+	if cfg.Logging.Sampling {
+		config.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.Logging.SamplingInitial,
+			Thereafter: cfg.Logging.SamplingThereafter,
+		}
+	}
+	config.DisableCaller = !cfg.Logging.Caller
+
+	// Build would pick the stack trace level itself; replace it with ours.
+	config.DisableStacktrace = true
+	var opts []zap.Option
+	if stacktrace := strings.ToLower(cfg.Logging.StacktraceLevel); stacktrace != "none" {
+		stacktraceLevel, err := zapcore.ParseLevel(stacktrace)
+		if err != nil {
+			return nil, level, fmt.Errorf("invalid LOG_STACKTRACE_LEVEL: %w", err)
+		}
+		opts = append(opts, zap.AddStacktrace(stacktraceLevel))
+	}
+
+	logger, err := config.Build(opts...)
+	return logger, level, err
 }
 
 // NewDevelopmentLogger creates a new zap logger for development (console encoder)
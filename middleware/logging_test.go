@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+	otherTrace  = "0af7651916cd43dd8448eb211c80319c"
+)
+
+func TestLoggingMiddlewareTraceContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		xTraceID    string
+		activeSpan  bool
+		wantTraceID string // empty: a new random ID
+		wantSampled bool
+	}{
+		{name: "active span wins over headers", traceParent: "00-" + otherTrace + "-" + testSpanID + "-00",
+			activeSpan: true, wantTraceID: testTraceID, wantSampled: true},
+		{name: "valid traceparent", traceParent: "00-" + testTraceID + "-" + testSpanID + "-01",
+			wantTraceID: testTraceID, wantSampled: true},
+		{name: "valid X-Trace-ID", xTraceID: testTraceID, wantTraceID: testTraceID},
+		{name: "malformed traceparent falls back to X-Trace-ID", traceParent: "00-abc-def-01",
+			xTraceID: testTraceID, wantTraceID: testTraceID},
+		{name: "zero trace ID", traceParent: "00-00000000000000000000000000000000-" + testSpanID + "-01"},
+		{name: "zero span ID", traceParent: "00-" + testTraceID + "-0000000000000000-01"},
+		{name: "forbidden version", traceParent: "ff-" + testTraceID + "-" + testSpanID + "-01"},
+		{name: "uppercase hex", traceParent: "00-" + "4BF92F3577B34DA6A3CE929D0E0E4736" + "-" + testSpanID + "-01"},
+		{name: "garbage", traceParent: "not a traceparent", xTraceID: "not-hex"},
+		{name: "no headers"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			r := gin.New()
+			if tt.activeSpan {
+				r.Use(func(c *gin.Context) {
+					tid, _ := trace.TraceIDFromHex(testTraceID)
+					sid, _ := trace.SpanIDFromHex(testSpanID)
+					sc := trace.NewSpanContext(trace.SpanContextConfig{
+						TraceID: tid, SpanID: sid, TraceFlags: trace.FlagsSampled,
+					})
+					c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), sc))
+				})
+			}
+			r.Use(LoggingMiddleware(zap.New(core)))
+			var handlerTraceID string
+			r.GET("/", func(c *gin.Context) { handlerTraceID = c.GetString("trace_id") })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.traceParent != "" {
+				req.Header.Set(TraceParentHeader, tt.traceParent)
+			}
+			if tt.xTraceID != "" {
+				req.Header.Set(TraceIDHeader, tt.xTraceID)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			echoed := trace.SpanContextFromContext(
+				propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(w.Header())))
+			if !echoed.IsValid() {
+				t.Fatalf("traceparent response header %q is not valid", w.Header().Get(TraceParentHeader))
+			}
+			traceID := echoed.TraceID().String()
+			if tt.wantTraceID != "" && traceID != tt.wantTraceID {
+				t.Errorf("trace ID = %s, want %s", traceID, tt.wantTraceID)
+			}
+			if tt.wantTraceID == "" && (traceID == testTraceID || traceID == otherTrace) {
+				t.Errorf("trace ID = %s, want a new one", traceID)
+			}
+			if echoed.IsSampled() != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", echoed.IsSampled(), tt.wantSampled)
+			}
+			if !tt.activeSpan && echoed.SpanID().String() == testSpanID {
+				t.Error("span ID of the caller was reused")
+			}
+			if got := w.Header().Get(TraceIDHeader); got != traceID {
+				t.Errorf("%s = %s, want %s", TraceIDHeader, got, traceID)
+			}
+			if handlerTraceID != traceID {
+				t.Errorf("trace_id in context = %s, want %s", handlerTraceID, traceID)
+			}
+
+			entries := logs.FilterMessage("HTTP request").All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d request entries, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if fields["trace_id"] != traceID || fields["span_id"] != echoed.SpanID().String() {
+				t.Errorf("logged trace_id=%v span_id=%v, want %s %s",
+					fields["trace_id"], fields["span_id"], traceID, echoed.SpanID())
+			}
+		})
+	}
+}
+
+func TestRequestLogOmitsQuerySecrets(t *testing.T) {
+	const secret = "s3cr3t-token"
+	gin.SetMode(gin.TestMode)
+	var ginOut bytes.Buffer
+	defer func(out, errOut io.Writer) { gin.DefaultWriter, gin.DefaultErrorWriter = out, errOut }(
+		gin.DefaultWriter, gin.DefaultErrorWriter)
+	gin.DefaultWriter, gin.DefaultErrorWriter = &ginOut, &ginOut
+
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+	r := gin.New()
+	r.Use(LoggingMiddleware(logger))
+	r.Use(RecoveryMiddleware(logger))
+	r.GET("/public/track", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/public/track/unsubscribe", func(c *gin.Context) { panic("unsubscribe failed") })
+
+	for _, target := range []string{
+		"/public/track?tracking_number=TRK1&customer_token=" + secret + "&postal_code=10115",
+		"/public/track/unsubscribe?token=" + secret,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	if logs.FilterMessage("HTTP request").Len() != 2 || logs.FilterMessage("Panic recovered").Len() != 1 {
+		t.Fatalf("unexpected log entries: %v", logs.All())
+	}
+	for _, entry := range logs.All() {
+		line := fmt.Sprint(entry.Message, entry.ContextMap())
+		if strings.Contains(line, secret) || strings.Contains(line, "10115") {
+			t.Errorf("logged request line leaks the query: %s", line)
+		}
+	}
+	if strings.Contains(ginOut.String(), secret) {
+		t.Errorf("gin output leaks the query: %s", ginOut.String())
+	}
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/duynhne/shipping-service/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor for OTLP/gRPC
+)
+
+// newSpanExporter creates the exporter selected by OTEL_TRACES_EXPORTER.
+func newSpanExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &fileSpanExporter{SpanExporter: exporter, file: f}, nil
+	}
+
+	tlsCfg, err := otlpTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Protocol == "grpc" {
+		return otlptracegrpc.New(ctx, otlpOptions[otlptracegrpc.Option]{
+			endpoint:    otlptracegrpc.WithEndpoint,
+			headers:     otlptracegrpc.WithHeaders,
+			compression: otlptracegrpc.WithCompressor("gzip"),
+			insecure:    otlptracegrpc.WithInsecure,
+			tls:         grpcTLS(otlptracegrpc.WithTLSCredentials),
+		}.build(cfg, tlsCfg)...)
+	}
+	return otlptracehttp.New(ctx, otlpOptions[otlptracehttp.Option]{
+		endpoint:    otlptracehttp.WithEndpoint,
+		headers:     otlptracehttp.WithHeaders,
+		compression: otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
+		insecure:    otlptracehttp.WithInsecure,
+		tls:         otlptracehttp.WithTLSClientConfig,
+	}.build(cfg, tlsCfg)...)
+}
+
+// newMetricExporter creates the exporter selected by OTEL_METRICS_EXPORTER,
+// connecting to the collector configured for traces.
+func newMetricExporter(ctx context.Context, cfg *config.Config) (sdkmetric.Exporter, error) {
+	if cfg.OTelMetrics.Exporter == "stdout" {
+		return stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+	}
+
+	tlsCfg, err := otlpTLSConfig(&cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Tracing.Protocol == "grpc" {
+		return otlpmetricgrpc.New(ctx, otlpOptions[otlpmetricgrpc.Option]{
+			endpoint:    otlpmetricgrpc.WithEndpoint,
+			headers:     otlpmetricgrpc.WithHeaders,
+			compression: otlpmetricgrpc.WithCompressor("gzip"),
+			insecure:    otlpmetricgrpc.WithInsecure,
+			tls:         grpcTLS(otlpmetricgrpc.WithTLSCredentials),
+		}.build(&cfg.Tracing, tlsCfg)...)
+	}
+	return otlpmetrichttp.New(ctx, otlpOptions[otlpmetrichttp.Option]{
+		endpoint:    otlpmetrichttp.WithEndpoint,
+		headers:     otlpmetrichttp.WithHeaders,
+		compression: otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+		insecure:    otlpmetrichttp.WithInsecure,
+		tls:         otlpmetrichttp.WithTLSClientConfig,
+	}.build(&cfg.Tracing, tlsCfg)...)
+}
+
+// newLogExporter creates the OTLP log exporter, connecting to the collector
+// configured for traces.
+func newLogExporter(ctx context.Context, cfg *config.TracingConfig) (sdklog.Exporter, error) {
+	tlsCfg, err := otlpTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Protocol == "grpc" {
+		return otlploggrpc.New(ctx, otlpOptions[otlploggrpc.Option]{
+			endpoint:    otlploggrpc.WithEndpoint,
+			headers:     otlploggrpc.WithHeaders,
+			compression: otlploggrpc.WithCompressor("gzip"),
+			insecure:    otlploggrpc.WithInsecure,
+			tls:         grpcTLS(otlploggrpc.WithTLSCredentials),
+		}.build(cfg, tlsCfg)...)
+	}
+	return otlploghttp.New(ctx, otlpOptions[otlploghttp.Option]{
+		endpoint:    otlploghttp.WithEndpoint,
+		headers:     otlploghttp.WithHeaders,
+		compression: otlploghttp.WithCompression(otlploghttp.GzipCompression),
+		insecure:    otlploghttp.WithInsecure,
+		tls:         otlploghttp.WithTLSClientConfig,
+	}.build(cfg, tlsCfg)...)
+}
+
+// otlpOptions holds the connection option constructors of one OTLP exporter
+// package, so traces, metrics and logs share one collector connection setup.
+type otlpOptions[O any] struct {
+	endpoint    func(string) O
+	headers     func(map[string]string) O
+	compression O
+	insecure    func() O
+	tls         func(*tls.Config) O
+}
+
+// build returns the endpoint, headers, gzip compression and TLS options for
+// cfg; a nil tlsCfg means a plaintext connection.
+func (o otlpOptions[O]) build(cfg *config.TracingConfig, tlsCfg *tls.Config) []O {
+	opts := []O{o.endpoint(cfg.Endpoint), o.headers(cfg.Headers), o.compression}
+	if tlsCfg == nil {
+		return append(opts, o.insecure())
+	}
+	return append(opts, o.tls(tlsCfg))
+}
+
+// grpcTLS adapts an OTLP/gRPC credentials option to a *tls.Config.
+func grpcTLS[O any](withCredentials func(credentials.TransportCredentials) O) func(*tls.Config) O {
+	return func(tlsCfg *tls.Config) O {
+		return withCredentials(credentials.NewTLS(tlsCfg))
+	}
+}
+
+// otlpTLSConfig returns the TLS settings for the collector connection, or nil
+// when OTEL_EXPORTER_OTLP_INSECURE is set. Without a CA file the system roots
+// are used.
+func otlpTLSConfig(cfg *config.TracingConfig) (*tls.Config, error) {
+	if cfg.Insecure {
+		return nil, nil
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OTLP CA certificate: %w", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("OTLP CA certificate file contains no PEM certificates")
+		}
+	}
+	if cfg.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OTLP client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// newSampler creates the sampler selected by OTEL_TRACES_SAMPLER. The
+// parent-based samplers keep the decision of a remote parent (e.g. the
+// order-service span in an incoming traceparent) and only roll the ratio for
+// new traces.
+func newSampler(cfg *config.TracingConfig) sdktrace.Sampler {
+	switch cfg.Sampler {
+	case "always_on":
+		return sdktrace.AlwaysSample()
+	case "always_off":
+		return sdktrace.NeverSample()
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(cfg.SampleRate)
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	default:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))
+	}
+}
+
+// fileSpanExporter closes its file once the exporter is shut down.
+type fileSpanExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileSpanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/duynhne/shipping-service/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewSamplerRespectsParent(t *testing.T) {
+	tid, _ := trace.TraceIDFromHex(testTraceID)
+	sid, _ := trace.SpanIDFromHex(testSpanID)
+	parent := func(flags trace.TraceFlags) context.Context {
+		return trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: tid, SpanID: sid, TraceFlags: flags, Remote: true,
+		}))
+	}
+
+	tests := []struct {
+		sampler string
+		rate    float64
+		ctx     context.Context
+		want    bool
+	}{
+		{"parentbased_traceidratio", 0, parent(trace.FlagsSampled), true},
+		{"parentbased_traceidratio", 1, parent(0), false},
+		{"parentbased_traceidratio", 1, context.Background(), true},
+		{"parentbased_traceidratio", 0, context.Background(), false},
+		{"traceidratio", 0, parent(trace.FlagsSampled), false},
+		{"parentbased_always_on", 0, parent(0), false},
+		{"always_on", 0, parent(0), true},
+		{"always_off", 1, parent(trace.FlagsSampled), false},
+	}
+	for _, tt := range tests {
+		sampler := newSampler(&config.TracingConfig{Sampler: tt.sampler, SampleRate: tt.rate})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+		_, span := tp.Tracer("test").Start(tt.ctx, "span")
+		if got := span.SpanContext().IsSampled(); got != tt.want {
+			t.Errorf("%s (rate %v) sampled = %v, want %v", tt.sampler, tt.rate, got, tt.want)
+		}
+		span.End()
+	}
+}
+
+func TestFileSpanExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exporter, err := newSpanExporter(context.Background(), &config.TracingConfig{Exporter: "file", FilePath: path})
+	if err != nil {
+		t.Fatalf("newSpanExporter: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := tp.Tracer("test").Start(context.Background(), "shipping.track")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"Name":"shipping.track"`) {
+		t.Errorf("trace file does not contain the span: %s", out)
+	}
+}
+
+func TestOTLPTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config.TracingConfig
+		wantTLS bool
+		wantErr bool
+	}{
+		{"insecure", config.TracingConfig{Insecure: true}, false, false},
+		{"system roots", config.TracingConfig{}, true, false},
+		{"CA without certificates", config.TracingConfig{CACertFile: notPEM}, false, true},
+		{"missing CA file", config.TracingConfig{CACertFile: filepath.Join(dir, "missing.pem")}, false, true},
+		{"missing client key pair", config.TracingConfig{
+			ClientCertFile: filepath.Join(dir, "client.pem"), ClientKeyFile: filepath.Join(dir, "client-key.pem"),
+		}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsCfg, err := otlpTLSConfig(&tt.cfg)
+			if (err != nil) != tt.wantErr || (tlsCfg != nil) != tt.wantTLS {
+				t.Errorf("otlpTLSConfig() = %v, %v; want TLS %v, error %v", tlsCfg, err, tt.wantTLS, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var rateLimitRejected = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rate_limit_rejected_total",
+		Help: "Requests rejected by the rate limiter",
+	},
+	[]string{"path", "key_type"},
+)
+
+// RateLimit is a token bucket: Burst requests at once, refilled at RPS per second.
+type RateLimit struct {
+	RPS   float64
+	Burst int
+}
+
+// APIKeyResolver identifies the caller of a request by a verified API key.
+// ok is false when the request carries no key or the key is not valid, so
+// made-up keys cannot be used to get fresh buckets.
+type APIKeyResolver func(r *http.Request) (caller string, ok bool)
+
+// RateLimiterConfig configures RateLimitMiddleware.
+type RateLimiterConfig struct {
+	Routes map[string]RateLimit // Limits by gin route path or gRPC full method; others are not limited
+	APIKey APIKeyResolver       // Optional; API key callers get their own bucket instead of their IP's
+}
+
+// rateLimitIdleSweep is how often buckets left full by idle clients are dropped.
+const rateLimitIdleSweep = time.Minute
+
+// RateLimiter holds one token bucket per route and client. Buckets live in
+// process memory, so each replica enforces the limits on its own.
+type RateLimiter struct {
+	routes map[string]RateLimit
+	apiKey APIKeyResolver
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	limit   RateLimit
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates a rate limiter.
+func NewRateLimiter(cfg RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		routes:  cfg.Routes,
+		apiKey:  cfg.APIKey,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// RateLimitMiddleware rejects requests over the route's limit with 429 and a
+// Retry-After header. Limited routes always get RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers
+// (IETF draft-ietf-httpapi-ratelimit-headers).
+//
+// Clients are keyed by c.ClientIP(), which honours the engine's trusted proxies
+// (gin.Engine.SetTrustedProxies); untrusted X-Forwarded-For headers are ignored.
+func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		limit, ok := rl.routes[path]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		keyType, client := "ip", c.ClientIP()
+		if rl.apiKey != nil {
+			if caller, ok := rl.apiKey(c.Request); ok {
+				keyType, client = "api_key", caller
+			}
+		}
+
+		allowed, remaining, retryAfter, reset := rl.take(path+"|"+keyType+":"+client, limit)
+
+		window := math.Ceil(float64(limit.Burst) / limit.RPS)
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(int(window)))
+
+		if !allowed {
+			rateLimitRejected.WithLabelValues(path, keyType).Inc()
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			writeRateLimitProblem(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// GRPCCallerResolver identifies the authenticated caller of a gRPC call, like
+// APIKeyResolver for HTTP requests.
+type GRPCCallerResolver func(ctx context.Context) (caller string, ok bool)
+
+// GRPCRateLimitInterceptor applies the limiter to unary calls, with routes keyed
+// by full method name ("/shipping.v1.ShippingService/TrackShipment"). Calls over
+// the limit fail with ResourceExhausted and a "retry-after" header in seconds.
+// Clients are keyed by peer IP; callers resolved by caller get their own bucket.
+// It must run after the interceptor that authenticates callers.
+func GRPCRateLimitInterceptor(rl *RateLimiter, caller GRPCCallerResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limit, ok := rl.routes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		keyType, client := "ip", peerIP(ctx)
+		if caller != nil {
+			if subject, ok := caller(ctx); ok {
+				keyType, client = "caller", subject
+			}
+		}
+
+		allowed, _, retryAfter, _ := rl.take(info.FullMethod+"|"+keyType+":"+client, limit)
+		if !allowed {
+			rateLimitRejected.WithLabelValues(info.FullMethod, keyType).Inc()
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(ceilSeconds(retryAfter))))
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded, retry after the retry-after delay")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// peerIP returns the IP address of the gRPC client, without the port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// take removes a token from key's bucket. It returns whether the request is
+// allowed, the whole tokens left, the wait until the next token and the time
+// until the bucket is full again.
+func (rl *RateLimiter) take(key string, limit RateLimit) (allowed bool, remaining int, retryAfter, reset time.Duration) {
+	now := rl.now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) >= rateLimitIdleSweep {
+		rl.sweep(now)
+	}
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: float64(limit.Burst), updated: now}
+		rl.buckets[key] = b
+	}
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retryAfter = seconds((1 - b.tokens) / limit.RPS)
+	}
+	reset = seconds((float64(limit.Burst) - b.tokens) / limit.RPS)
+	return allowed, int(b.tokens), retryAfter, reset
+}
+
+// sweep drops buckets that have refilled completely; recreating one is
+// equivalent. The caller holds rl.mu.
+func (rl *RateLimiter) sweep(now time.Time) {
+	for key, b := range rl.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastSweep = now
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.RPS)
+		b.updated = now
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ceilSeconds rounds up so clients never retry too early.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// writeRateLimitProblem writes the same RFC 7807 body as the v1 API's other errors.
+func writeRateLimitProblem(c *gin.Context) {
+	problem := gin.H{
+		"type":     "urn:shipping-service:problem:rate-limited",
+		"title":    "Too many requests",
+		"status":   http.StatusTooManyRequests,
+		"detail":   "Rate limit exceeded, retry after the Retry-After delay",
+		"instance": c.Request.URL.Path,
+	}
+	if traceID := c.GetString("trace_id"); traceID != "" {
+		problem["trace_id"] = traceID
+	}
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, problem)
+}
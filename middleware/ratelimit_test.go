@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const limitedPath = "/shipping/v1/public/track"
+
+type rateLimitRequest struct {
+	remoteAddr string
+	forwarded  string // X-Forwarded-For
+	apiKey     string
+	path       string
+	advance    time.Duration // Clock advance before the request
+	wantStatus int
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	resolver := func(r *http.Request) (string, bool) {
+		if r.Header.Get("X-API-Key") == "partner-key" {
+			return "partner", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name     string
+		requests []rateLimitRequest
+	}{
+		{
+			name: "burst then reject",
+			requests: []rateLimitRequest{
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "198.51.100.2:1234", wantStatus: http.StatusOK}, // Other client, own bucket
+			},
+		},
+		{
+			name: "refills over time",
+			requests: []rateLimitRequest{
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "198.51.100.1:1234", advance: time.Second, wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "unlisted route is not limited",
+			requests: []rateLimitRequest{
+				{remoteAddr: "198.51.100.1:1234", path: "/other", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", path: "/other", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", path: "/other", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "trusted proxy forwards client IP",
+			requests: []rateLimitRequest{
+				{remoteAddr: "10.0.0.5:80", forwarded: "203.0.113.7", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.5:80", forwarded: "203.0.113.7", wantStatus: http.StatusOK},
+				{remoteAddr: "10.0.0.5:80", forwarded: "203.0.113.7", wantStatus: http.StatusTooManyRequests},
+				{remoteAddr: "10.0.0.5:80", forwarded: "203.0.113.8", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "untrusted peer cannot spoof X-Forwarded-For",
+			requests: []rateLimitRequest{
+				{remoteAddr: "198.51.100.1:1234", forwarded: "203.0.113.1", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", forwarded: "203.0.113.2", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", forwarded: "203.0.113.3", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name: "valid API key gets its own bucket",
+			requests: []rateLimitRequest{
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", apiKey: "partner-key", wantStatus: http.StatusOK},
+				{remoteAddr: "198.51.100.1:1234", apiKey: "made-up", wantStatus: http.StatusTooManyRequests},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			rl := NewRateLimiter(RateLimiterConfig{
+				Routes: map[string]RateLimit{limitedPath: {RPS: 1, Burst: 2}},
+				APIKey: resolver,
+			})
+			rl.now = func() time.Time { return now }
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			if err := r.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+				t.Fatal(err)
+			}
+			r.Use(RateLimitMiddleware(rl))
+			r.GET(limitedPath, func(c *gin.Context) { c.Status(http.StatusOK) })
+			r.GET("/other", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for i, req := range tt.requests {
+				now = now.Add(req.advance)
+				path := req.path
+				if path == "" {
+					path = limitedPath
+				}
+				httpReq := httptest.NewRequest(http.MethodGet, path, nil)
+				httpReq.RemoteAddr = req.remoteAddr
+				if req.forwarded != "" {
+					httpReq.Header.Set("X-Forwarded-For", req.forwarded)
+				}
+				if req.apiKey != "" {
+					httpReq.Header.Set("X-API-Key", req.apiKey)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httpReq)
+
+				if w.Code != req.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, w.Code, req.wantStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(RateLimiterConfig{Routes: map[string]RateLimit{limitedPath: {RPS: 0.5, Burst: 2}}})
+	rl.now = func() time.Time { return now }
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("trace_id", "trace-1") })
+	r.Use(RateLimitMiddleware(rl))
+	r.GET(limitedPath, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, limitedPath, nil))
+		return w
+	}
+
+	first := serve()
+	for header, want := range map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "2", // One token to refill at 0.5/s
+		"RateLimit-Policy":    "2;w=4",
+	} {
+		if got := first.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if first.Header().Get("Retry-After") != "" {
+		t.Error("Retry-After set on an allowed request")
+	}
+
+	serve()
+	rejected := serve()
+	if rejected.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rejected.Code)
+	}
+	if got := rejected.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if got := rejected.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+	if ct := rejected.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	var problem struct {
+		Type    string `json:"type"`
+		Status  int    `json:"status"`
+		TraceID string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(rejected.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Type != "urn:shipping-service:problem:rate-limited" || problem.Status != 429 || problem.TraceID != "trace-1" {
+		t.Errorf("problem = %+v", problem)
+	}
+}
+
+func TestRateLimiterSweepsIdleBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewRateLimiter(RateLimiterConfig{})
+	rl.now = func() time.Time { return now }
+	limit := RateLimit{RPS: 1, Burst: 5}
+
+	rl.take("a", limit)
+	rl.take("b", limit)
+	now = now.Add(rateLimitIdleSweep)
+	rl.take("c", limit)
+
+	if len(rl.buckets) != 1 {
+		t.Errorf("buckets = %d, want only the active one", len(rl.buckets))
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces masked values.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders carry credentials and are always masked.
+var sensitiveHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-API-Key", "X-Customer-Token",
+}
+
+// redactionPattern masks values that look like personal data or credentials,
+// wherever they appear in a string.
+type redactionPattern struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+var redactionPatterns = []redactionPattern{
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`), "Bearer " + redacted},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redacted}, // JWT
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+	{
+		regexp.MustCompile(`(?i)\b\d{1,5}\s+(?:[a-z0-9.'-]+\s+){0,4}` +
+			`(?:street|st|avenue|ave|road|rd|boulevard|blvd|lane|ln|drive|dr|court|ct|way|place|pl|square|sq|highway|hwy)\b\.?`),
+		"[ADDRESS]",
+	},
+	{regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?\b\d{2,4}\)?[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`), "[PHONE]"},
+}
+
+// Redactor masks personal data and credentials in logged payloads: JSON
+// fields by name, anything matching the built-in patterns (bearer tokens,
+// JWTs, emails, street addresses, phone numbers) and credential headers.
+type Redactor struct {
+	fields   map[string]bool
+	suffixes []string
+}
+
+// NewRedactor masks the given JSON field and header names. Names match
+// case-insensitively and ignoring "_" and "-", and headers also without their
+// "X-" prefix, so "api_key" masks "apiKey" and "X-Api-Key" too. A leading "*"
+// matches any name ending in the rest: "*token" masks "unsubscribe_token".
+func NewRedactor(fields []string) *Redactor {
+	r := &Redactor{fields: make(map[string]bool, len(fields))}
+	for _, f := range fields {
+		if suffix, ok := strings.CutPrefix(f, "*"); ok {
+			r.suffixes = append(r.suffixes, normalizeFieldName(suffix))
+			continue
+		}
+		r.fields[normalizeFieldName(f)] = true
+	}
+	return r
+}
+
+func normalizeFieldName(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+// masks reports whether the field or header name is configured for masking.
+func (r *Redactor) masks(name string) bool {
+	name = normalizeFieldName(name)
+	if r.fields[name] {
+		return true
+	}
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks every pattern match in s.
+func (r *Redactor) String(s string) string {
+	for _, p := range redactionPatterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// JSON masks configured fields and pattern matches in a JSON document. ok is
+// false when body is not valid JSON; the caller must not log it then, since
+// fields cannot be told apart.
+func (r *Redactor) JSON(body []byte) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return "", false
+	}
+	out, err := json.Marshal(r.value(doc))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func (r *Redactor) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if r.masks(k) {
+				v[k] = redacted
+			} else {
+				v[k] = r.value(child)
+			}
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = r.value(child)
+		}
+		return v
+	case string:
+		return r.String(v)
+	default:
+		return v
+	}
+}
+
+// Headers flattens h for logging with credentials and configured names masked.
+func (r *Redactor) Headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		out[name] = r.String(strings.Join(values, ", "))
+		if r.masks(name) || r.masks(strings.TrimPrefix(name, "X-")) {
+			out[name] = redacted
+		}
+	}
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+	return out
+}
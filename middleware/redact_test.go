@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/duynhne/shipping-service/config"
+	"github.com/duynhne/shipping-service/internal/core/domain"
+)
+
+func TestRedactorString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"call +84 912 345 678 today", "call [PHONE] today"},
+		{"(555) 123-4567", "[PHONE]"},
+		{"mail jane.doe@example.com", "mail [EMAIL]"},
+		{"ship to 221B Baker Street, London", "ship to 221B Baker Street, London"},
+		{"ship to 42 Wallaby Way, Sydney", "ship to [ADDRESS], Sydney"},
+		{"Authorization: Bearer abc.def-ghi", "Authorization: Bearer [REDACTED]"},
+		{"token eyJhbGciOi.eyJzdWIiOi.c2lnbmF0dXJl", "token [REDACTED]"},
+		{"TRK1234567890", "TRK1234567890"},
+		{"2026-01-15T10:00:00Z", "2026-01-15T10:00:00Z"},
+	}
+	r := NewRedactor(nil)
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := r.String(tt.in); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{
+			"configured fields at any depth",
+			`{"order_id":123456789,"Secret":"s3cr3t","sub":[{"apiKey":"k","carrier":"ups"}]}`,
+			`{"Secret":"[REDACTED]","order_id":123456789,"sub":[{"apiKey":"[REDACTED]","carrier":"ups"}]}`,
+			true,
+		},
+		{
+			"patterns in string values",
+			`{"description":"left with jane@example.com"}`,
+			`{"description":"left with [EMAIL]"}`,
+			true,
+		},
+		{"invalid JSON", `{"secret":`, "", false},
+	}
+	r := NewRedactor([]string{"secret", "api_key"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.JSON([]byte(tt.body))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("JSON() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRedactorDefaultsMaskServiceBodies(t *testing.T) {
+	t.Setenv("LOG_REDACT_FIELDS", "")
+	r := NewRedactor(config.Load().Logging.RedactFields)
+	orderID := 42
+
+	tests := []struct {
+		name   string
+		body   any
+		masked []string
+		kept   []string
+	}{
+		{
+			"subscribe request",
+			domain.SubscribeRequest{TrackingNumber: "1Z999AA10123456784", WebhookURL: "https://hooks.example.com/t?key=abc"},
+			[]string{"webhook_url"},
+			[]string{"tracking_number"},
+		},
+		{
+			"subscription response",
+			domain.TrackingSubscription{
+				ID: 7, ShipmentID: 1, TrackingNumber: "1Z999AA10123456784", Channel: "webhook",
+				Target: "https://hooks.example.com/t?key=abc", UnsubscribeToken: "3f2a9c", CreatedAt: "2026-01-15T10:00:00Z",
+			},
+			[]string{"target", "unsubscribe_token"},
+			[]string{"tracking_number", "channel", "created_at"},
+		},
+		{
+			"merchant webhook subscription",
+			domain.WebhookSubscription{
+				ID: 3, URL: "https://merchant.example.com/hooks", Secret: "whsec_abc", EventTypes: []string{"shipment.created"},
+				Carrier: "ups", OrderID: &orderID, Active: true,
+			},
+			[]string{"url", "secret"},
+			[]string{"carrier", "active"},
+		},
+		{
+			"signing secret and customer token",
+			map[string]string{"signing_secret": "whsec_abc", "customer_token": "ct_123", "status": "delivered"},
+			[]string{"signing_secret", "customer_token"},
+			[]string{"status"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, ok := r.JSON(body)
+			if !ok {
+				t.Fatalf("JSON(%s) not ok", body)
+			}
+			var got, orig map[string]any
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(body, &orig); err != nil {
+				t.Fatal(err)
+			}
+			for _, field := range tt.masked {
+				if got[field] != redacted {
+					t.Errorf("%s = %v, want %s", field, got[field], redacted)
+				}
+			}
+			for _, field := range tt.kept {
+				if got[field] != orig[field] {
+					t.Errorf("%s = %v, want %v", field, got[field], orig[field])
+				}
+			}
+		})
+	}
+}
+
+func TestRedactorHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer abc")
+	h.Set("X-API-Key", "key")
+	h.Set("X-Signature", "sig")
+	h.Set("X-Trace-ID", "abc123")
+
+	got := NewRedactor([]string{"signature"}).Headers(h)
+	want := map[string]string{
+		"Authorization": redacted,
+		"X-Api-Key":     redacted,
+		"X-Signature":   redacted,
+		"X-Trace-Id":    "abc123",
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("header %s = %q, want %q", name, got[name], v)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/duynhne/shipping-service/config"
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// instrumentationName names the meter and logger of this package.
+const instrumentationName = "github.com/duynhne/shipping-service/middleware"
+
+// otelRequestDuration mirrors the Prometheus request_duration_seconds histogram
+// (same buckets and method/path/code attributes) for the OTLP pipeline; a
+// collector exporting to Prometheus names it request_duration_seconds too.
+// It records nothing until InitMetrics sets the global MeterProvider.
+var otelRequestDuration, _ = otel.Meter(instrumentationName).Float64Histogram(
+	"request_duration",
+	metric.WithUnit("s"),
+	metric.WithDescription("Duration of HTTP requests in seconds"),
+	metric.WithExplicitBucketBoundaries(requestDurationBuckets...),
+)
+
+// recordRequestDuration records a request in the OTel RED histogram. The SDK
+// takes exemplars from the span in ctx.
+func recordRequestDuration(ctx context.Context, method, path, code string, seconds float64) {
+	otelRequestDuration.Record(ctx, seconds, metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("path", path),
+		attribute.String("code", code),
+	))
+}
+
+// InitMetrics initializes the OpenTelemetry MeterProvider, exporting every
+// OTEL_METRIC_EXPORT_INTERVAL over the OTLP connection configured for traces.
+// Prometheus metrics are unaffected.
+func InitMetrics(cfg *config.Config) (*sdkmetric.MeterProvider, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exporter, err := newMetricExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s metric exporter: %w", cfg.OTelMetrics.Exporter, err)
+	}
+	res, _ := CreateResource(context.Background()) // partial failure is acceptable; fallback resource is valid
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(cfg.OTelMetrics.Interval),
+		)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+	return mp, nil
+}
+
+// InitLogs initializes the OpenTelemetry LoggerProvider that receives logs
+// bridged by WithOTelLogs, over the OTLP connection configured for traces.
+func InitLogs(cfg *config.Config) (*sdklog.LoggerProvider, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exporter, err := newLogExporter(ctx, &cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+	res, _ := CreateResource(context.Background()) // partial failure is acceptable; fallback resource is valid
+
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	), nil
+}
+
+// WithOTelLogs returns logger that also sends every entry enabled at level to
+// provider. Entries logged with a request's logger (see LoggingMiddleware)
+// carry its trace and span IDs.
+func WithOTelLogs(logger *zap.Logger, provider *sdklog.LoggerProvider, level zap.AtomicLevel) (*zap.Logger, error) {
+	bridge, err := zapcore.NewIncreaseLevelCore(
+		otelzap.NewCore(instrumentationName, otelzap.WithLoggerProvider(provider)), level)
+	if err != nil {
+		return nil, err
+	}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, bridge)
+	})), nil
+}
+
+// spanContextField carries a span context to the OTel logs bridge, which uses
+// it to correlate the entry with its trace; other cores skip the field.
+func spanContextField(sc trace.SpanContext) zap.Field {
+	return zap.Field{
+		Key:       "context",
+		Type:      zapcore.SkipType,
+		Interface: trace.ContextWithSpanContext(context.Background(), sc),
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordingLogExporter keeps exported log records in memory.
+type recordingLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (*recordingLogExporter) Shutdown(context.Context) error   { return nil }
+func (*recordingLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestWithOTelLogs(t *testing.T) {
+	exporter := &recordingLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	core, logs := observer.New(zap.DebugLevel)
+
+	logger, err := WithOTelLogs(zap.New(core), provider, level)
+	if err != nil {
+		t.Fatalf("WithOTelLogs: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LoggingMiddleware(logger))
+	r.GET("/", func(c *gin.Context) {
+		GetLoggerFromGinContext(c).Info("handled")
+		GetLoggerFromGinContext(c).Debug("below level")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceParentHeader, "00-"+testTraceID+"-"+testSpanID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	if len(exporter.records) != 2 {
+		t.Fatalf("exported %d records, want 2 (handled, HTTP request)", len(exporter.records))
+	}
+	for _, rec := range exporter.records {
+		if got := rec.TraceID().String(); got != testTraceID {
+			t.Errorf("%q: trace ID = %s, want %s", rec.Body().AsString(), got, testTraceID)
+		}
+		if !rec.SpanID().IsValid() {
+			t.Errorf("%q: no span ID", rec.Body().AsString())
+		}
+	}
+	// The original core still receives everything it enables, without the context field
+	if entry := logs.FilterMessage("handled").All(); len(entry) != 1 || entry[0].ContextMap()["context"] != nil {
+		t.Errorf("zap entries for handled = %+v", entry)
+	}
+}
+
+// Instruments bind to the first global MeterProvider only, so it is installed once per test binary.
+var (
+	metricReader         = sdkmetric.NewManualReader()
+	installMeterProvider sync.Once
+)
+
+func TestRecordRequestDurationMirrorsRED(t *testing.T) {
+	installMeterProvider.Do(func() {
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader)))
+	})
+	reader := metricReader
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(PrometheusMiddleware())
+	r.GET("/shipments/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/shipments/1", nil))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "request_duration" {
+				continue
+			}
+			hist := m.Data.(metricdata.Histogram[float64])
+			if m.Unit != "s" || len(hist.DataPoints) != 1 {
+				t.Fatalf("request_duration unit %q with %d points", m.Unit, len(hist.DataPoints))
+			}
+			dp := hist.DataPoints[0]
+			for key, want := range map[string]string{"method": "GET", "path": "/shipments/:id", "code": "404"} {
+				if v, _ := dp.Attributes.Value(attribute.Key(key)); v.AsString() != want {
+					t.Errorf("attribute %s = %q, want %q", key, v.AsString(), want)
+				}
+			}
+			if len(dp.Bounds) != len(requestDurationBuckets) {
+				t.Errorf("%d bucket bounds, want %d", len(dp.Bounds), len(requestDurationBuckets))
+			}
+			return
+		}
+	}
+	t.Fatal("request_duration was not recorded")
+}
@@ -13,7 +13,6 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
@@ -40,7 +39,7 @@ func InitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
 	}
 
 	// Validate tracing configuration
-	if cfg.Tracing.Endpoint == "" {
+	if cfg.Tracing.Exporter == "otlp" && cfg.Tracing.Endpoint == "" {
 		return nil, errors.New("OTEL_COLLECTOR_ENDPOINT is required when tracing is enabled")
 	}
 	if cfg.Tracing.SampleRate < 0 || cfg.Tracing.SampleRate > 1.0 {
@@ -51,16 +50,11 @@ func InitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Create OTLP HTTP exporter with compression
+	// Create the span exporter: OTLP (HTTP or gRPC, optionally TLS) or stdout/file for local debugging
 	// OTel Collector endpoint: otel-collector-opentelemetry-collector.monitoring.svc.cluster.local:4318 (OTLP HTTP)
-	exporter, err := otlptracehttp.New(
-		ctx,
-		otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint),
-		otlptracehttp.WithInsecure(), // Use TLS in production
-		otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
-	)
+	exporter, err := newSpanExporter(ctx, &cfg.Tracing)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Tracing.Exporter, err)
 	}
 
 	// Auto-detect service information from Kubernetes environment
@@ -79,7 +73,8 @@ func InitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
 	// Create tracer provider with batch export configuration
 	// BatchTimeout: How often to flush spans (default: 5s)
 	// ExportTimeout: Max time to wait for export (default: 30s)
-	// SampleRate: Percentage of traces to sample (10% production, 100% dev)
+	// Sampler: by default follows the caller's sampling decision and samples
+	// SampleRate of new traces (10% production, 100% dev)
 	tracerProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter,
 			sdktrace.WithBatchTimeout(5*time.Second),
@@ -87,7 +82,7 @@ func InitTracing(cfg *config.Config) (*sdktrace.TracerProvider, error) {
 			sdktrace.WithMaxExportBatchSize(cfg.Tracing.MaxExportBatchSize),
 		),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRate)),
+		sdktrace.WithSampler(newSampler(&cfg.Tracing)),
 	)
 
 	// Set global tracer provider